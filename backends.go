@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+
+	"github.com/hhhapz/doc"
+)
+
+// backend is a documentation backend, which counts its searches.
+type backend struct {
+	name string
+	doc.Searcher
+
+	succeeded atomic.Int64
+	failed    atomic.Int64
+}
+
+// fallbackSearcher searches an ordered list of backends, until one of them
+// finds the package. Any error falls through to the next backend, so that
+// documentation keeps working when one of them is down or cannot be parsed.
+type fallbackSearcher struct {
+	backends []*backend
+}
+
+// backendSearcher is a searcher that also returns the name of the backend
+// that found a package, so that it can be kept with the package.
+type backendSearcher interface {
+	doc.Searcher
+	searchBackend(ctx context.Context, module string) (doc.Package, string, error)
+}
+
+var _ backendSearcher = (*fallbackSearcher)(nil)
+
+// Search searches the backends in order. If all of them fail, their errors
+// are joined.
+func (f *fallbackSearcher) Search(ctx context.Context, module string) (doc.Package, error) {
+	pkg, _, err := f.searchBackend(ctx, module)
+	return pkg, err
+}
+
+// searchBackend is Search, but also returns the name of the backend that found
+// the package.
+func (f *fallbackSearcher) searchBackend(ctx context.Context, module string) (doc.Package, string, error) {
+	var errs []error
+	for _, b := range f.backends {
+		pkg, err := b.Search(ctx, module)
+		if err == nil {
+			b.succeeded.Add(1)
+			if len(f.backends) > 1 {
+				log.Printf("Package %q found by the %s backend", module, b.name)
+			}
+			return pkg, b.name, nil
+		}
+
+		b.failed.Add(1)
+		errs = append(errs, fmt.Errorf("%s: %w", b.name, err))
+		// The remaining backends would fail the same way.
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return doc.Package{}, "", errors.Join(errs...)
+}
+
+// searchBackend searches for a package with searcher, and returns the name of
+// the backend that found it if searcher reports it.
+func searchBackend(ctx context.Context, searcher doc.Searcher, module string) (doc.Package, string, error) {
+	if s, ok := searcher.(backendSearcher); ok {
+		return s.searchBackend(ctx, module)
+	}
+	pkg, err := searcher.Search(ctx, module)
+	return pkg, "", err
+}
+
+// stats describes the number of successful and failed searches of each
+// backend.
+func (f *fallbackSearcher) stats() string {
+	lines := make([]string, 0, len(f.backends))
+	for _, b := range f.backends {
+		lines = append(lines, fmt.Sprintf("%s: %d found, %d failed", b.name, b.succeeded.Load(), b.failed.Load()))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	"github.com/hhhapz/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+type searcherFunc func(ctx context.Context, module string) (doc.Package, error)
+
+func (f searcherFunc) Search(ctx context.Context, module string) (doc.Package, error) {
+	return f(ctx, module)
+}
+
+func TestFallbackSearcher(t *testing.T) {
+	down := searcherFunc(func(ctx context.Context, module string) (doc.Package, error) {
+		return doc.Package{}, doc.InvalidStatusError(503)
+	})
+	local := searcherFunc(func(ctx context.Context, module string) (doc.Package, error) {
+		if module != "fmt" {
+			return doc.Package{}, errors.New("not found")
+		}
+		return doc.Package{URL: module}, nil
+	})
+	f := &fallbackSearcher{backends: []*backend{
+		{name: "pkgsite", Searcher: down},
+		{name: "local", Searcher: local},
+	}}
+
+	pkg, err := f.Search(context.Background(), "fmt")
+	assert.NoError(t, err)
+	assert.Equal(t, "fmt", pkg.URL)
+
+	_, err = f.Search(context.Background(), "example.com/missing")
+	assert.ErrorIs(t, err, doc.InvalidStatusError(503))
+	assert.Contains(t, err.Error(), "local: not found")
+
+	assert.Equal(t, "pkgsite: 0 found, 2 failed\nlocal: 1 found, 1 failed", f.stats())
+
+	// The cache keeps the backend that found a package.
+	c := newSearchCache(&missingSearcher{Searcher: f, missing: newMissingCache(time.Minute)}, "", 0, 0)
+	_, err = c.Search(context.Background(), "fmt")
+	assert.NoError(t, err)
+	assert.Equal(t, "local", c.backend("fmt"))
+}
+
+func TestNewBackends(t *testing.T) {
+	f, err := newBackends(configuration{}, gosrc.Split{}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, f.backends, 1)
+	assert.Equal(t, "pkgsite", f.backends[0].name)
+
+	f, err = newBackends(configuration{Backend: "pkgsite, proxy,local"}, gosrc.Split{}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, f.backends, 3)
+	assert.Equal(t, "local", f.backends[2].name)
+
+	_, err = newBackends(configuration{Backend: "godocs"}, gosrc.Split{}, nil)
+	assert.Error(t, err)
+}
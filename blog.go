@@ -45,16 +45,21 @@ func (b *botState) handleBlog(e *gateway.InteractionCreateEvent, d *discord.Comm
 
 	log.Printf("%s used blog(%q)", e.User.Tag(), query)
 
+	b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+		Type: api.MessageInteractionWithSource,
+		Data: b.blogResponse(query, false),
+	})
+}
+
+// blogResponse returns the response to a blog query, which is shared by the
+// slash and text commands. public is set for text commands, whose responses
+// cannot be ephemeral.
+func (b *botState) blogResponse(query string, public bool) *api.InteractionResponseData {
 	if len(query) < 3 || len(query) > 20 {
-		embed := failEmbed("Error", "Your query must be between 3 and 20 characters.")
-		b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
-			Type: api.MessageInteractionWithSource,
-			Data: &api.InteractionResponseData{
-				Flags:  discord.EphemeralMessage,
-				Embeds: &[]discord.Embed{embed},
-			},
-		})
-		return
+		return &api.InteractionResponseData{
+			Flags:  discord.EphemeralMessage,
+			Embeds: &[]discord.Embed{failEmbed("Error", "Your query must be between 3 and 20 characters.")},
+		}
 	}
 
 	fromTitle, fromDesc, total := blog.MatchAll(b.articles, query)
@@ -63,38 +68,26 @@ func (b *botState) handleBlog(e *gateway.InteractionCreateEvent, d *discord.Comm
 
 	switch total {
 	case 0:
-		b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
-			Type: api.MessageInteractionWithSource,
-			Data: &api.InteractionResponseData{
-				Flags:  discord.EphemeralMessage,
-				Embeds: &[]discord.Embed{failEmbed("Error", fmt.Sprintf("No results found for %q", query))},
-			},
-		})
-		return
+		return &api.InteractionResponseData{
+			Flags:  discord.EphemeralMessage,
+			Embeds: &[]discord.Embed{failEmbed("Error", fmt.Sprintf("No results found for %q", query))},
+		}
 
 	case 1:
-		b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
-			Type: api.MessageInteractionWithSource,
-			Data: &api.InteractionResponseData{
-				Embeds: &[]discord.Embed{articles[0].Display()},
-			},
-		})
-		return
+		return &api.InteractionResponseData{
+			Embeds: &[]discord.Embed{articles[0].Display()},
+		}
 
 	case 2:
-		b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
-			Type: api.MessageInteractionWithSource,
-			Data: &api.InteractionResponseData{
-				Embeds: &[]discord.Embed{
-					{
-						Title:  fmt.Sprintf("Blog: %q", query),
-						Fields: fields,
-						Color:  accentColor,
-					},
+		return &api.InteractionResponseData{
+			Embeds: &[]discord.Embed{
+				{
+					Title:  fmt.Sprintf("Blog: %q", query),
+					Fields: fields,
+					Color:  accentColor,
 				},
 			},
-		})
-		return
+		}
 	}
 
 	comps := make(discord.ContainerComponents, 1, 2)
@@ -113,31 +106,32 @@ func (b *botState) handleBlog(e *gateway.InteractionCreateEvent, d *discord.Comm
 	}
 
 	p := int(math.Ceil(float64(total) / float64(5)))
-	b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
-		Type: api.MessageInteractionWithSource,
-		Data: &api.InteractionResponseData{
-			Flags: discord.EphemeralMessage,
-			Embeds: &[]discord.Embed{
-				{
-					Title: fmt.Sprintf("Blog: %d Results", total),
-					Footer: &discord.EmbedFooter{
-						Text: fmt.Sprintf("Page 1 of %d\nTo display publicly, select a single post", p),
-					},
-					Fields: append([]discord.EmbedField{
-						{
-							Name:  "Search Term",
-							Value: fmt.Sprintf("%q", query),
-						},
-					}, fields...),
-					Color: accentColor,
+	return &api.InteractionResponseData{
+		Flags: discord.EphemeralMessage,
+		Embeds: &[]discord.Embed{
+			{
+				Title: fmt.Sprintf("Blog: %d Results", total),
+				Footer: &discord.EmbedFooter{
+					Text: blogFooter(1, p, public),
 				},
+				Fields: append([]discord.EmbedField{
+					{
+						Name:  "Search Term",
+						Value: fmt.Sprintf("%q", query),
+					},
+				}, fields...),
+				Color: accentColor,
 			},
-			Components: &comps,
 		},
-	})
+		Components: &comps,
+	}
 }
 
 func (b *botState) handleBlogComponent(e *gateway.InteractionCreateEvent, data discord.ComponentInteraction, cmd string) {
+	if !b.checkTextOwner(e) {
+		return
+	}
+
 	switch cmd {
 	case "display":
 		b.BlogDisplay(e, data.(*discord.StringSelectInteraction).Values[0])
@@ -196,7 +190,7 @@ func (b *botState) handleBlogComponent(e *gateway.InteractionCreateEvent, data d
 				{
 					Title: fmt.Sprintf("Blog: %d Results", total),
 					Footer: &discord.EmbedFooter{
-						Text: fmt.Sprintf("Page %d of %d\nTo display publicly, select a single post", cur, p),
+						Text: blogFooter(cur, p, e.Message.Flags&discord.EphemeralMessage == 0),
 					},
 					Fields: append([]discord.EmbedField{
 						{
@@ -214,6 +208,16 @@ func (b *botState) handleBlogComponent(e *gateway.InteractionCreateEvent, data d
 
 var pageRe = regexp.MustCompile(`Page (\d+) of (\d+)`)
 
+// blogFooter returns the footer of a page of blog results. Results that are
+// already public are not told how to display a post publicly.
+func blogFooter(page, pages int, public bool) string {
+	text := fmt.Sprintf("Page %d of %d", page, pages)
+	if !public {
+		text += "\nTo display publicly, select a single post"
+	}
+	return text
+}
+
 func (b *botState) BlogDisplay(e *gateway.InteractionCreateEvent, url string) {
 	var article blog.Article
 	for _, a := range b.articles {
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/hhhapz/doc"
+)
+
+// browseLimit is the number of items on a page of a browse menu, which leaves
+// room for the previous and next page options within Discord's limit of 25.
+const browseLimit = 23
+
+// browsePage is the prefix of the values of the options that change the page
+// of a browse menu. Colons are not allowed in import paths or identifiers, so
+// it cannot be mistaken for a query.
+const browsePage = "page:"
+
+var parentOption = discord.SelectOption{
+	Label:       "Parent package",
+	Value:       "parent",
+	Description: "Show the package that contains this one.",
+	Emoji:       &discord.ComponentEmoji{Name: "⤴️"},
+}
+
+// parentPackage returns the query of the package that contains the package of
+// the symbol. Outside the standard library, the first three elements of the
+// path are the module root, such as github.com/owner/repo, so their parents are
+// not packages.
+func parentPackage(sym symbol) (string, bool) {
+	importPath, version := sym.importPath()
+	parent := path.Dir(importPath)
+	switch {
+	case parent == ".":
+		return "", false
+	case !gosrc.IsStdlib(importPath) && strings.Count(parent, "/") < 2:
+		return "", false
+	}
+
+	if version != "" {
+		parent += "@" + version
+	}
+	return parent, true
+}
+
+// browseMenu is a select menu to browse the packages or symbols related to a
+// docs query. The value of each option is its index, such as "sub:3", as the
+// queries to show can be longer than Discord allows values to be.
+type browseMenu struct {
+	name        string
+	placeholder string
+	opts        []discord.SelectOption
+	queries     []string
+}
+
+// indexMenu returns a menu of the options, whose values are the queries to
+// show. They are moved to the queries of the menu, and replaced with their
+// index.
+func indexMenu(name, placeholder string, opts []discord.SelectOption) browseMenu {
+	m := browseMenu{name: name, placeholder: placeholder, opts: opts}
+	for i := range opts {
+		m.queries = append(m.queries, opts[i].Value)
+		opts[i].Value = name + ":" + strconv.Itoa(i)
+	}
+	return m
+}
+
+// query returns the query of the option with the value.
+func (m browseMenu) query(value string) (string, bool) {
+	index, ok := strings.CutPrefix(value, m.name+":")
+	if !ok {
+		return "", false
+	}
+	i, err := strconv.Atoi(index)
+	if err != nil || i < 0 || i >= len(m.queries) {
+		return "", false
+	}
+	return m.queries[i], true
+}
+
+// browseMenus returns the menus of the symbol. Packages can be browsed into
+// their sub-packages, types, functions, and constants and variables, and types
+// into their methods and constructors.
+func browseMenus(sym symbol) []browseMenu {
+	importPath, version := sym.importPath()
+	// Symbols are queried with spaces, so that the version of the package is
+	// not mistaken for part of the symbol.
+	base := sym.pkg.URL
+
+	switch sym.kind {
+	case kindPackage:
+		var subs []discord.SelectOption
+		for _, sub := range sym.pkg.Subpackages {
+			if !strings.HasPrefix(sub, importPath+"/") {
+				continue
+			}
+			value := sub
+			if version != "" {
+				value += "@" + version
+			}
+			subs = append(subs, discord.SelectOption{
+				Label: truncate(strings.TrimPrefix(sub, importPath+"/"), 100),
+				Value: value,
+			})
+		}
+
+		var types, funcs, consts, vars []discord.SelectOption
+		for _, typ := range sym.pkg.Types {
+			types = append(types, browseOption(base, typ.Name, "", typ.Comment))
+		}
+		for _, fn := range sym.pkg.Functions {
+			funcs = append(funcs, browseOption(base, fn.Name, "", fn.Comment))
+		}
+		for _, v := range sym.pkg.ConstantMap {
+			consts = append(consts, browseOption(base, v.Name, "const", v.Comment))
+		}
+		for _, v := range sym.pkg.VariableMap {
+			vars = append(vars, browseOption(base, v.Name, "var", v.Comment))
+		}
+
+		// Constants and variables share a menu, as Discord only allows five
+		// rows of components on a message.
+		return []browseMenu{
+			indexMenu("sub", "Browse sub-packages", subs),
+			indexMenu("types", "Browse types", sortOptions(types)),
+			indexMenu("funcs", "Browse functions", sortOptions(funcs)),
+			indexMenu("values", "Browse constants and variables", append(sortOptions(consts), sortOptions(vars)...)),
+		}
+
+	case kindType:
+		var methods, ctors []discord.SelectOption
+		for _, m := range sym.typ.Methods {
+			methods = append(methods, browseOption(base+" "+sym.typ.Name, m.Name, "", m.Comment))
+		}
+		for _, fn := range sym.typ.TypeFunctions {
+			ctors = append(ctors, browseOption(base, fn.Name, "", fn.Comment))
+		}
+		return []browseMenu{
+			indexMenu("methods", "Browse methods", sortOptions(methods)),
+			indexMenu("ctors", "Browse constructors", sortOptions(ctors)),
+		}
+	}
+	return nil
+}
+
+// browseOption returns the option to show a symbol, with the synopsis of its
+// doc comment as the description. The kind, if set, is shown before the
+// synopsis. Its value is the query of the symbol, until indexMenu replaces it.
+func browseOption(base, name, kind string, c doc.Comment) discord.SelectOption {
+	desc := synopsis(c)
+	if kind != "" {
+		desc = strings.TrimSpace(kind + " " + desc)
+	}
+	return discord.SelectOption{
+		Label:       truncate(name, 100),
+		Value:       base + " " + name,
+		Description: truncate(desc, 100),
+	}
+}
+
+// sortOptions sorts the options of a browse menu by their label, and then
+// their value, so that their indexes are the same every time the menu is
+// rendered.
+func sortOptions(opts []discord.SelectOption) []discord.SelectOption {
+	sort.Slice(opts, func(i, j int) bool {
+		if opts[i].Label != opts[j].Label {
+			return opts[i].Label < opts[j].Label
+		}
+		return opts[i].Value < opts[j].Value
+	})
+	return opts
+}
+
+// synopsis returns the first sentence of a doc comment.
+func synopsis(c doc.Comment) string {
+	text, _, _ := strings.Cut(c.Text(), "\n\n")
+	text = strings.Join(strings.Fields(text), " ")
+	if i := strings.Index(text, ". "); i != -1 {
+		text = text[:i+1]
+	}
+	return text
+}
+
+// browseComponents returns a row for each menu of the symbol that has
+// options, or none if sym is nil. The menu named by menu is shown at the page,
+// and all others on their first page.
+func browseComponents(id string, sym *symbol, menu string, page int) discord.ContainerComponents {
+	if sym == nil {
+		return nil
+	}
+
+	var components discord.ContainerComponents
+	for _, m := range browseMenus(*sym) {
+		if len(m.opts) == 0 {
+			continue
+		}
+		p := 0
+		if m.name == menu {
+			p = page
+		}
+		components = append(components, &discord.ActionRowComponent{
+			browseSelect("docs.browse."+id+"."+m.name, m.placeholder, m.opts, p),
+		})
+	}
+	return components
+}
+
+// browseQuery returns the query of the option with the value in the menu of
+// the symbol.
+func browseQuery(sym symbol, menu, value string) (string, bool) {
+	for _, m := range browseMenus(sym) {
+		if m.name == menu {
+			return m.query(value)
+		}
+	}
+	return "", false
+}
+
+// browseSelect returns a select menu with the options. If there are more than
+// 25, they are split into pages, with options to go to the previous and the
+// next page.
+func browseSelect(customID, placeholder string, opts []discord.SelectOption, page int) *discord.StringSelectComponent {
+	sel := &discord.StringSelectComponent{
+		CustomID:    discord.ComponentID(customID),
+		Placeholder: placeholder,
+		Options:     opts,
+	}
+	if len(opts) <= 25 {
+		return sel
+	}
+
+	pages := (len(opts) + browseLimit - 1) / browseLimit
+	page = min(max(page, 0), pages-1)
+	start := page * browseLimit
+
+	sel.Options = nil
+	if page > 0 {
+		sel.Options = append(sel.Options, discord.SelectOption{
+			Label: "Previous page",
+			Value: browsePage + strconv.Itoa(page-1),
+			Emoji: &discord.ComponentEmoji{Name: "⬅️"},
+		})
+	}
+	sel.Options = append(sel.Options, opts[start:min(start+browseLimit, len(opts))]...)
+	if page < pages-1 {
+		sel.Options = append(sel.Options, discord.SelectOption{
+			Label: "Next page",
+			Value: browsePage + strconv.Itoa(page+1),
+			Emoji: &discord.ComponentEmoji{Name: "➡️"},
+		})
+	}
+	sel.Placeholder += fmt.Sprintf(" (%d/%d)", page+1, pages)
+	return sel
+}
+
+// truncate shortens s to at most n bytes, for fields that Discord limits in
+// length. It is cut between runes, so that it stays valid UTF-8.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	i := n - 3
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return s[:i] + "..."
+}
+
+// handleBrowseComponent handles the browse menus of a docs message. The
+// command is formatted as "<id>.<menu>". Choosing an item replaces the message
+// with its documentation, while the page options only change the menu.
+func (b *botState) handleBrowseComponent(e *gateway.InteractionCreateEvent, component discord.ComponentInteraction, cmd string) {
+	sel, ok := component.(*discord.StringSelectInteraction)
+	if !ok || len(sel.Values) == 0 {
+		return
+	}
+	id, menu, _ := strings.Cut(cmd, ".")
+
+	mu.Lock()
+	data, ok := interactionMap[id]
+	mu.Unlock()
+
+	switch {
+	case !ok:
+		b.respondError(e, expired)
+		return
+	case e.User.ID != data.userID:
+		b.respondError(e, notOwner)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), componentTimeout)
+	defer cancel()
+
+	sym, err := b.lookup(ctx, *e.User, data.query)
+	if err != nil {
+		b.respondError(e, expired)
+		return
+	}
+
+	value := sel.Values[0]
+	pageStr, ok := strings.CutPrefix(value, browsePage)
+	if !ok {
+		query, ok := browseQuery(sym, menu, value)
+		if !ok {
+			b.respondError(e, expired)
+			return
+		}
+		b.navigate(ctx, e, data, query)
+		return
+	}
+
+	// Only the menu changes, so that the rest of the message, such as the
+	// page of expanded docs, is kept.
+	page, _ := strconv.Atoi(pageStr)
+	menus := map[discord.ComponentID]discord.InteractiveComponent{}
+	for _, row := range browseComponents(id, &sym, menu, page) {
+		for _, c := range *row.(*discord.ActionRowComponent) {
+			menus[c.ID()] = c
+		}
+	}
+
+	components := e.Message.Components
+	for _, row := range components {
+		row, ok := row.(*discord.ActionRowComponent)
+		if !ok {
+			continue
+		}
+		for i, c := range *row {
+			if menu, ok := menus[c.ID()]; ok {
+				(*row)[i] = menu
+			}
+		}
+	}
+
+	b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+		Type: api.UpdateMessage,
+		Data: &api.InteractionResponseData{
+			Components: &components,
+		},
+	})
+}
+
+// navigate replaces a docs message with the documentation of another query,
+// such as a sub-package, a symbol or the parent package.
+func (b *botState) navigate(ctx context.Context, e *gateway.InteractionCreateEvent, data *interactionData, query string) {
+	log.Printf("%s used docs browse(%q)", e.User.Tag(), query)
+
+	mu.Lock()
+	data.query = query
+	mu.Unlock()
+
+	embed, sym, more := b.interactionDocs(ctx, *e.User, data)
+	components := docsComponents(data.id, sym, false, more)
+	b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+		Type: api.UpdateMessage,
+		Data: &api.InteractionResponseData{
+			Embeds:     &[]discord.Embed{embed},
+			Components: &components,
+		},
+	})
+}
+
+// respondError shows an error privately to the user of an interaction.
+func (b *botState) respondError(e *gateway.InteractionCreateEvent, msg string) {
+	b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+		Type: api.MessageInteractionWithSource,
+		Data: &api.InteractionResponseData{
+			Flags:  discord.EphemeralMessage,
+			Embeds: &[]discord.Embed{failEmbed("Error", msg)},
+		},
+	})
+}
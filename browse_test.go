@@ -0,0 +1,106 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/hhhapz/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParentPackage(t *testing.T) {
+	cases := []struct {
+		name, want string
+		ok         bool
+	}{
+		{"net/http", "net", true},
+		{"net/http@go1.18", "net@go1.18", true},
+		{"fmt", "", false},
+		{"github.com/hhhapz/doc", "", false},
+		{"github.com/hhhapz/doc/godocs", "github.com/hhhapz/doc", true},
+		{"golang.org/x/tools/go/packages", "golang.org/x/tools/go", true},
+	}
+	for _, c := range cases {
+		parent, ok := parentPackage(symbol{pkg: doc.Package{Name: c.name, URL: c.name}})
+		assert.Equal(t, c.ok, ok, c.name)
+		assert.Equal(t, c.want, parent, c.name)
+	}
+}
+
+func TestBrowseSelect(t *testing.T) {
+	var opts []discord.SelectOption
+	for i := range 50 {
+		opts = append(opts, discord.SelectOption{Label: strconv.Itoa(i), Value: strconv.Itoa(i)})
+	}
+
+	sel := browseSelect("id", "Browse", opts[:25], 0)
+	assert.Len(t, sel.Options, 25)
+	assert.Equal(t, "Browse", sel.Placeholder)
+
+	sel = browseSelect("id", "Browse", opts, 0)
+	assert.Len(t, sel.Options, browseLimit+1)
+	assert.Equal(t, "0", sel.Options[0].Value)
+	assert.Equal(t, browsePage+"1", sel.Options[browseLimit].Value)
+	assert.Equal(t, "Browse (1/3)", sel.Placeholder)
+
+	sel = browseSelect("id", "Browse", opts, 2)
+	assert.Len(t, sel.Options, 50-2*browseLimit+1)
+	assert.Equal(t, browsePage+"1", sel.Options[0].Value)
+	assert.Equal(t, "49", sel.Options[len(sel.Options)-1].Value)
+}
+
+func TestBrowseMenus(t *testing.T) {
+	comment := doc.Comment{doc.Paragraph("Client is an HTTP client. It is safe for concurrent use.")}
+	client := doc.Type{
+		Name:    "Client",
+		Comment: comment,
+		Methods: map[string]doc.Method{
+			"get": {For: "Client", Function: doc.Function{Name: "Get"}},
+			"do":  {For: "Client", Function: doc.Function{Name: "Do"}},
+		},
+		TypeFunctions: map[string]doc.Function{"newclient": {Name: "NewClient"}},
+	}
+	pkg := doc.Package{
+		Name:        "net/http@go1.18",
+		URL:         "net/http@go1.18",
+		Types:       map[string]doc.Type{"client": client},
+		Functions:   map[string]doc.Function{"newclient": {Name: "NewClient"}, "get": {Name: "Get"}},
+		ConstantMap: map[string]doc.Variable{"methodget": {Name: "MethodGet"}},
+		VariableMap: map[string]doc.Variable{"errbodyreadafterclose": {Name: "ErrBodyReadAfterClose"}},
+		Subpackages: []string{"net/http/httptest", "net/http/pprof"},
+	}
+
+	menus := browseMenus(symbol{pkg: pkg})
+	assert.Len(t, menus, 4)
+	assert.Equal(t, "httptest", menus[0].opts[0].Label)
+	assert.Equal(t, "sub:1", menus[0].opts[1].Value)
+	query, ok := menus[0].query("sub:1")
+	assert.True(t, ok)
+	assert.Equal(t, "net/http/pprof@go1.18", query)
+	_, ok = menus[0].query("sub:2")
+	assert.False(t, ok)
+	assert.Equal(t, "types:0", menus[1].opts[0].Value)
+	assert.Equal(t, "net/http@go1.18 Client", menus[1].queries[0])
+	assert.Equal(t, "Client is an HTTP client.", menus[1].opts[0].Description)
+	assert.Equal(t, "Get", menus[2].opts[0].Label)
+	assert.Equal(t, "MethodGet", menus[3].opts[0].Label)
+	assert.Equal(t, "const", menus[3].opts[0].Description)
+	assert.Equal(t, "var", menus[3].opts[1].Description)
+
+	menus = browseMenus(symbol{pkg: pkg, kind: kindType, typ: client})
+	assert.Len(t, menus, 2)
+	assert.Equal(t, []string{"net/http@go1.18 Client Do", "net/http@go1.18 Client Get"}, menus[0].queries)
+	assert.Equal(t, "methods:1", menus[0].opts[1].Value)
+	assert.Equal(t, []string{"net/http@go1.18 NewClient"}, menus[1].queries)
+
+	// Values are short, however long the queries are.
+	long := strings.Repeat("long/", 30) + "pkg"
+	pkg.URL = long + "@v0.0.0-20240101000000-abcdefabcdef"
+	for _, m := range browseMenus(symbol{pkg: pkg}) {
+		for _, opt := range m.opts {
+			assert.LessOrEqual(t, len(opt.Value), 100)
+		}
+	}
+}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hhhapz/doc"
+)
+
+const (
+	// cacheMaxAge is how long packages are cached before they are searched
+	// again, so that the documentation of hot packages is refreshed too.
+	cacheMaxAge = 72 * time.Hour
+	// cacheMaxIdle is how long packages that are not used are kept.
+	cacheMaxIdle = 72 * time.Hour
+	// fetchTimeout is how long a package is searched for before giving up.
+	// Searches that wait for it may give up earlier.
+	fetchTimeout = 30 * time.Second
+)
+
+func init() {
+	// Comments are lists of notes, which gob can only encode if their
+	// concrete types are registered.
+	gob.Register(doc.Comment{})
+	gob.Register(doc.Heading(""))
+	gob.Register(doc.Paragraph(""))
+	gob.Register(doc.Pre(""))
+}
+
+// searchCache caches the packages found by a searcher. Unlike
+// doc.NewCachedSearcher, which can only cache pages of a package site, it
+// works with any searcher, such as the one of the local backend.
+//
+// If dir is set, packages are also written to files in it, so that the cache
+// survives restarts. They are read lazily, when a package is first searched.
+//
+// The packages in memory are limited to maxEntries and to about maxBytes, once
+// gob encoded. When either is exceeded, the least recently used packages are
+// evicted from memory, but not from the cache directory. A limit of 0 means no
+// limit.
+//
+// Concurrent searches of a package that is not cached share a single search.
+//
+// If the searcher reports which backend found a package, its name is kept
+// with the package, in memory and in the cache directory.
+type searchCache struct {
+	doc.Searcher
+	dir        string
+	maxEntries int
+	maxBytes   int64
+
+	mu       sync.Mutex
+	cache    map[string]*doc.CachedPackage
+	backends map[string]string
+	sizes    map[string]int64
+	size     int64
+	calls    map[string]*searchCall
+
+	evicted atomic.Int64
+	pruned  atomic.Int64
+}
+
+var _ doc.CachedSearcher = (*searchCache)(nil)
+
+func newSearchCache(searcher doc.Searcher, dir string, maxEntries int, maxBytes int64) *searchCache {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			log.Printf("Could not create the cache directory, packages are only cached in memory: %v", err)
+			dir = ""
+		}
+	}
+	return &searchCache{
+		Searcher:   searcher,
+		dir:        dir,
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		cache:      map[string]*doc.CachedPackage{},
+		backends:   map[string]string{},
+		sizes:      map[string]int64{},
+		calls:      map[string]*searchCall{},
+	}
+}
+
+// searchCall is a search of a package that is in progress. done is closed
+// once pkg and err are set.
+type searchCall struct {
+	done chan struct{}
+	pkg  doc.Package
+	err  error
+}
+
+// Search returns the cached package, or searches for it if it is not cached or
+// was cached longer than cacheMaxAge ago. Updated is set to the time of the
+// last search of a package.
+//
+// If ctx is done before the package is found, its error is returned, but the
+// package is still searched for, so that it is cached for the next search.
+func (c *searchCache) Search(ctx context.Context, module string) (doc.Package, error) {
+	c.mu.Lock()
+	if cpkg, ok := c.cache[module]; ok && time.Since(cpkg.Created) <= cacheMaxAge {
+		cpkg.Updated = time.Now()
+		c.mu.Unlock()
+		return cpkg.Package, nil
+	}
+	call, ok := c.calls[module]
+	if !ok {
+		call = &searchCall{done: make(chan struct{})}
+		c.calls[module] = call
+		go c.fetch(module, call)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.pkg, call.err
+	case <-ctx.Done():
+		return doc.Package{}, ctx.Err()
+	}
+}
+
+// fetch reads a package from the cache directory, or searches for it with a
+// timeout of fetchTimeout, and adds it to the cache.
+func (c *searchCache) fetch(module string, call *searchCall) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, module)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	if cpkg, backend, size, ok := c.read(module); ok {
+		c.add(module, cpkg, backend, size)
+		call.pkg = cpkg.Package
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	var backend string
+	call.pkg, backend, call.err = searchBackend(ctx, c.Searcher, module)
+	if call.err != nil {
+		return
+	}
+
+	cpkg := &doc.CachedPackage{
+		Package: call.pkg,
+		Created: time.Now(),
+		Updated: time.Now(),
+	}
+	size := c.write(module, cpkg, backend)
+	c.add(module, cpkg, backend, size)
+}
+
+// add adds a package to the cache, and evicts the least recently used
+// packages if the cache is over its limits. The package that is added is
+// never evicted, even if it is larger than maxBytes on its own.
+func (c *searchCache) add(module string, cpkg *doc.CachedPackage, backend string, size int64) {
+	if size == 0 {
+		size = encodedSize(cpkg)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.delete(module)
+	c.cache[module] = cpkg
+	if backend != "" {
+		c.backends[module] = backend
+	}
+	c.sizes[module] = size
+	c.size += size
+
+	for len(c.cache) > 1 && (c.maxEntries > 0 && len(c.cache) > c.maxEntries ||
+		c.maxBytes > 0 && c.size > c.maxBytes) {
+		var oldest string
+		for m, cp := range c.cache {
+			if m != module && (oldest == "" || cp.Updated.Before(c.cache[oldest].Updated)) {
+				oldest = m
+			}
+		}
+		c.touch(oldest, c.cache[oldest].Updated)
+		c.delete(oldest)
+		c.evicted.Add(1)
+	}
+}
+
+// delete deletes a package from memory. c.mu must be held.
+func (c *searchCache) delete(module string) {
+	delete(c.cache, module)
+	delete(c.backends, module)
+	c.size -= c.sizes[module]
+	delete(c.sizes, module)
+}
+
+// backend returns the name of the backend that found a cached package, or ""
+// if it is not cached or the backend is not known.
+func (c *searchCache) backend(module string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.backends[module]
+}
+
+// WithCache calls f with the cache locked, so that it can be read and
+// modified. Packages that are deleted from the map are not deleted from the
+// cache directory, use remove instead.
+func (c *searchCache) WithCache(f func(cache map[string]*doc.CachedPackage)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f(c.cache)
+
+	for module, size := range c.sizes {
+		if _, ok := c.cache[module]; !ok {
+			c.size -= size
+			delete(c.sizes, module)
+			delete(c.backends, module)
+		}
+	}
+}
+
+// stats returns the number of packages in memory and their approximate size
+// in bytes, and how many were evicted for the limits of the cache and pruned
+// for not being used.
+func (c *searchCache) stats() (entries int, size, evicted, pruned int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.cache), c.size, c.evicted.Load(), c.pruned.Load()
+}
+
+// full reports whether the cache is at one of its limits, so that adding a
+// package would evict another.
+func (c *searchCache) full() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.maxEntries > 0 && len(c.cache) >= c.maxEntries ||
+		c.maxBytes > 0 && c.size >= c.maxBytes
+}
+
+// remove removes the packages that match from the cache, including those in
+// the cache directory that are not in memory. match is called with the time
+// that the package was last used. The removed modules are returned.
+func (c *searchCache) remove(match func(module string, used time.Time) bool) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := map[string]bool{}
+	for module, cpkg := range c.cache {
+		if match(module, cpkg.Updated) {
+			c.delete(module)
+			removed[module] = true
+		}
+	}
+
+	if c.dir != "" {
+		entries, err := os.ReadDir(c.dir)
+		if err != nil {
+			log.Printf("Could not read the cache directory: %v", err)
+		}
+		for _, entry := range entries {
+			module, ok := cacheModule(entry.Name())
+			if _, cached := c.cache[module]; !ok || cached {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			if removed[module] || match(module, info.ModTime()) {
+				os.Remove(filepath.Join(c.dir, entry.Name()))
+				removed[module] = true
+			}
+		}
+	}
+
+	modules := make([]string, 0, len(removed))
+	for module := range removed {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// prune removes the packages that were last used longer than maxIdle ago.
+func (c *searchCache) prune(maxIdle time.Duration) []string {
+	removed := c.remove(func(_ string, used time.Time) bool {
+		return time.Since(used) > maxIdle
+	})
+	c.pruned.Add(int64(len(removed)))
+	return removed
+}
+
+// read reads a package from the cache directory, and returns it with the
+// backend that found it and the size of its file. Packages older than
+// cacheMaxAge are ignored, and removed once the cache is pruned.
+func (c *searchCache) read(module string) (*doc.CachedPackage, string, int64, bool) {
+	if c.dir == "" {
+		return nil, "", 0, false
+	}
+
+	f, err := os.Open(filepath.Join(c.dir, cacheFile(module)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, "", 0, false
+	}
+	if err != nil {
+		log.Printf("Could not read cached package %q: %v", module, err)
+		return nil, "", 0, false
+	}
+	defer f.Close()
+
+	var cpkg doc.CachedPackage
+	dec := gob.NewDecoder(f)
+	if err := dec.Decode(&cpkg); err != nil {
+		log.Printf("Could not decode cached package %q: %v", module, err)
+		return nil, "", 0, false
+	}
+	if time.Since(cpkg.Created) > cacheMaxAge {
+		return nil, "", 0, false
+	}
+	// The backend follows the package. Files that were cached before it was
+	// kept end after the package, and have no backend.
+	var backend string
+	if err := dec.Decode(&backend); err != nil && !errors.Is(err, io.EOF) {
+		log.Printf("Could not decode the backend of cached package %q: %v", module, err)
+	}
+	cpkg.Updated = time.Now()
+
+	var size int64
+	if info, err := f.Stat(); err == nil {
+		size = info.Size()
+	}
+	return &cpkg, backend, size, true
+}
+
+// write writes a package and the backend that found it to the cache
+// directory, and returns the size of its file. The file is renamed into
+// place, so that a package is never read while it is written.
+func (c *searchCache) write(module string, cpkg *doc.CachedPackage, backend string) int64 {
+	if c.dir == "" {
+		return 0
+	}
+
+	f, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		log.Printf("Could not cache package %q: %v", module, err)
+		return 0
+	}
+	defer os.Remove(f.Name())
+
+	w := &countingWriter{w: f}
+	enc := gob.NewEncoder(w)
+	err = enc.Encode(cpkg)
+	if err == nil {
+		err = enc.Encode(backend)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filepath.Join(c.dir, cacheFile(module)))
+	}
+	if err != nil {
+		log.Printf("Could not cache package %q: %v", module, err)
+		return 0
+	}
+	return w.n
+}
+
+// touch sets the modification time of the file of a package to the time it
+// was last used, so that the file is pruned once the package is unused for
+// long enough. c.mu must be held.
+func (c *searchCache) touch(module string, used time.Time) {
+	if c.dir == "" {
+		return
+	}
+	err := os.Chtimes(filepath.Join(c.dir, cacheFile(module)), used, used)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("Could not update cached package %q: %v", module, err)
+	}
+}
+
+// encodedSize returns the size of the package once gob encoded, which
+// approximates the memory it uses.
+func encodedSize(cpkg *doc.CachedPackage) int64 {
+	w := &countingWriter{w: io.Discard}
+	if err := gob.NewEncoder(w).Encode(cpkg); err != nil {
+		return 0
+	}
+	return w.n
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// cacheFile returns the name of the file that a module is cached in. Modules
+// are escaped, so that each of them is a single file in the cache directory.
+func cacheFile(module string) string {
+	return url.PathEscape(module) + ".gob"
+}
+
+// cacheModule returns the module cached in the file, as named by cacheFile.
+func cacheModule(name string) (string, bool) {
+	escaped, ok := strings.CutSuffix(name, ".gob")
+	if !ok {
+		return "", false
+	}
+	module, err := url.PathUnescape(escaped)
+	return module, err == nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hhhapz/doc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchCacheDisk(t *testing.T) {
+	dir := t.TempDir()
+	searches := 0
+	searcher := searcherFunc(func(ctx context.Context, module string) (doc.Package, error) {
+		searches++
+		return doc.Package{
+			URL:      module,
+			Overview: doc.Comment{doc.Paragraph("Package fmt."), doc.Pre("fmt.Println()\n")},
+		}, nil
+	})
+
+	c := newSearchCache(searcher, dir, 0, 0)
+	pkg, err := c.Search(context.Background(), "golang.org/x/mod@v0.1.0")
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "golang.org%2Fx%2Fmod@v0.1.0.gob"))
+
+	// A new cache reads the package from the directory, as after a restart.
+	c = newSearchCache(searcher, dir, 0, 0)
+	cached, err := c.Search(context.Background(), "golang.org/x/mod@v0.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, pkg, cached)
+	assert.Equal(t, 1, searches)
+	assert.Empty(t, c.backend("golang.org/x/mod@v0.1.0"))
+
+	// The backend that found a package is read with it.
+	f := &fallbackSearcher{backends: []*backend{{name: "local", Searcher: searcher}}}
+	c = newSearchCache(f, dir, 0, 0)
+	_, err = c.Search(context.Background(), "fmt")
+	require.NoError(t, err)
+	c = newSearchCache(f, dir, 0, 0)
+	_, err = c.Search(context.Background(), "fmt")
+	require.NoError(t, err)
+	assert.Equal(t, "local", c.backend("fmt"))
+	assert.Equal(t, 2, searches)
+
+	// Packages that have not been read yet are removed from the directory,
+	// while the state directory is not mistaken for a package.
+	state := configuration{CacheDir: dir}.statedir()
+	require.NoError(t, os.MkdirAll(state, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(state, "index.gob"), nil, 0o644))
+	c = newSearchCache(searcher, dir, 0, 0)
+	removed := c.remove(func(string, time.Time) bool {
+		return true
+	})
+	assert.Equal(t, []string{"fmt", "golang.org/x/mod@v0.1.0"}, removed)
+	assert.NoFileExists(t, filepath.Join(dir, "golang.org%2Fx%2Fmod@v0.1.0.gob"))
+	assert.FileExists(t, filepath.Join(state, "index.gob"))
+}
+
+func TestSearchCachePrune(t *testing.T) {
+	dir := t.TempDir()
+	searcher := searcherFunc(func(ctx context.Context, module string) (doc.Package, error) {
+		return doc.Package{URL: module}, nil
+	})
+
+	c := newSearchCache(searcher, dir, 0, 0)
+	for _, module := range []string{"fmt", "io", "os"} {
+		_, err := c.Search(context.Background(), module)
+		require.NoError(t, err)
+	}
+
+	// Packages are pruned by when they were last used, not cached.
+	old := time.Now().Add(-2 * cacheMaxIdle)
+	c.WithCache(func(cache map[string]*doc.CachedPackage) {
+		cache["fmt"].Updated = old
+		cache["io"].Created = old
+		delete(cache, "os")
+	})
+	require.NoError(t, os.Chtimes(filepath.Join(dir, cacheFile("os")), old, old))
+
+	assert.Equal(t, []string{"fmt", "os"}, c.prune(cacheMaxIdle))
+	assert.NoFileExists(t, filepath.Join(dir, cacheFile("fmt")))
+	assert.NoFileExists(t, filepath.Join(dir, cacheFile("os")))
+	assert.FileExists(t, filepath.Join(dir, cacheFile("io")))
+
+	entries, _, _, pruned := c.stats()
+	assert.Equal(t, 1, entries)
+	assert.EqualValues(t, 2, pruned)
+}
+
+func TestSearchCacheEvict(t *testing.T) {
+	searches := map[string]int{}
+	searcher := searcherFunc(func(ctx context.Context, module string) (doc.Package, error) {
+		searches[module]++
+		return doc.Package{URL: module}, nil
+	})
+
+	c := newSearchCache(searcher, "", 2, 0)
+	for _, module := range []string{"fmt", "io", "fmt", "os", "fmt", "io"} {
+		_, err := c.Search(context.Background(), module)
+		require.NoError(t, err)
+	}
+	// io was evicted by os, as fmt was used more recently, and os by io.
+	assert.Equal(t, map[string]int{"fmt": 1, "io": 2, "os": 1}, searches)
+
+	entries, size, evicted, _ := c.stats()
+	assert.Equal(t, 2, entries)
+	assert.Positive(t, size)
+	assert.EqualValues(t, 2, evicted)
+
+	// The package that is added is kept, even if it is over the size limit.
+	c = newSearchCache(searcher, "", 0, 1)
+	for _, module := range []string{"fmt", "io"} {
+		_, err := c.Search(context.Background(), module)
+		require.NoError(t, err)
+	}
+	c.WithCache(func(cache map[string]*doc.CachedPackage) {
+		assert.Contains(t, cache, "io")
+		assert.Len(t, cache, 1)
+	})
+}
+
+func TestSearchCacheCoalesce(t *testing.T) {
+	var searches atomic.Int64
+	release := make(chan struct{})
+	searcher := searcherFunc(func(ctx context.Context, module string) (doc.Package, error) {
+		searches.Add(1)
+		<-release
+		return doc.Package{URL: module}, nil
+	})
+	c := newSearchCache(searcher, "", 0, 0)
+
+	// A search that gives up does not cancel the shared search.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := c.Search(ctx, "net/http")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pkg, err := c.Search(context.Background(), "net/http")
+			assert.NoError(t, err)
+			assert.Equal(t, "net/http", pkg.URL)
+		}()
+	}
+	close(release)
+	wg.Wait()
+	assert.EqualValues(t, 1, searches.Load())
+}
+
+func TestCacheModule(t *testing.T) {
+	for _, module := range []string{"fmt", "github.com/hhhapz/doc@v0.5.0", "example.com/a b"} {
+		got, ok := cacheModule(cacheFile(module))
+		assert.True(t, ok)
+		assert.Equal(t, module, got)
+	}
+
+	_, ok := cacheModule(".tmp-123")
+	assert.False(t, ok)
+}
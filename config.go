@@ -3,20 +3,79 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"go/build"
 	"log"
 	"os"
+	"path/filepath"
+	"runtime"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/dustin/go-humanize"
 )
 
 type configuration struct {
-	Prefix      string             `json:"prefix"`
-	Token       string             `json:"-"`
-	Permissions commandPermissions `json:"permissions"`
+	// Prefix is the prefix of text commands, such as "dr." for
+	// "dr.docs fmt". Prefixes overrides it in guilds. If empty, text
+	// commands are disabled.
+	Prefix      string                     `json:"prefix"`
+	Prefixes    map[discord.GuildID]string `json:"prefixes,omitempty"`
+	Token       string                     `json:"-"`
+	Permissions commandPermissions         `json:"permissions"`
 
 	Aliases map[string]string `json:"aliases"`
 
+	// Proxy is the list of module proxies, in the GOPROXY format, used to
+	// download module source code, and documentation with the proxy backend.
+	// file:// proxies are supported. If empty, GOPROXY is used.
+	Proxy string `json:"proxy,omitempty"`
+	// GOROOT is the Go installation used for standard library source code.
+	// If empty, the GOROOT the bot was built with is used.
+	GOROOT string `json:"goroot,omitempty"`
+
+	// Backend is a comma separated list of the backends that documentation
+	// is loaded from, which are tried in order. "pkgsite", the default,
+	// scrapes pkg.go.dev. The other backends build it from source with
+	// go/doc, reading the standard library from GOROOT. "proxy" downloads
+	// modules from Proxy, while "local" reads them from ModDirs and ModCache,
+	// so that no network access is needed.
+	Backend string `json:"backend,omitempty"`
+	// ModDirs are module directories, such as checkouts of their
+	// repositories, used by the local backend.
+	ModDirs []string `json:"moddirs,omitempty"`
+	// ModCache is the module cache used by the local backend. If empty,
+	// GOMODCACHE is used, falling back to $GOPATH/pkg/mod.
+	ModCache string `json:"modcache,omitempty"`
+
+	// CacheDir is the directory that packages are cached in, so that they
+	// are not searched again after a restart. If empty, "cache" in the
+	// working directory is used.
+	CacheDir string `json:"cachedir,omitempty"`
+	// CacheEntries is the maximum number of packages cached in memory, and
+	// CacheSize their maximum approximate size, such as "256MB". When either
+	// is exceeded, the least recently used packages are evicted. If zero or
+	// empty, there is no limit.
+	CacheEntries int    `json:"cacheentries,omitempty"`
+	CacheSize    string `json:"cachesize,omitempty"`
+	// MissingModuleTTL and MissingSymbolTTL are how long modules, and
+	// symbols in modules, that were not found are remembered, such as "10m".
+	// If empty, they are remembered for five minutes, and "0s" disables it.
+	MissingModuleTTL string `json:"missingmodulettl,omitempty"`
+	MissingSymbolTTL string `json:"missingsymbolttl,omitempty"`
+
+	// PrewarmInterval is the time between the searches of the packages that
+	// are cached in the background after startup: the standard library, and
+	// the most used modules. If empty, it is half a second, and "0s"
+	// disables prewarming.
+	PrewarmInterval string `json:"prewarminterval,omitempty"`
+
+	// Index is the module index that module paths are autocompleted from,
+	// such as a local stand-in for https://index.golang.org, the default.
+	// "off" disables it.
+	Index string `json:"index,omitempty"`
+
 	Blacklist map[discord.Snowflake]struct{} `json:"blacklist"`
 }
 
@@ -87,10 +146,114 @@ func configFromBytes(data []byte) (configuration, error) {
 	if config.Blacklist == nil {
 		config.Blacklist = map[discord.Snowflake]struct{}{}
 	}
+	if config.Prefixes == nil {
+		config.Prefixes = map[discord.GuildID]string{}
+	}
 
 	return config, nil
 }
 
+// prefix returns the prefix of text commands in the guild.
+func (c configuration) prefix(guild discord.GuildID) string {
+	if prefix, ok := c.Prefixes[guild]; ok {
+		return prefix
+	}
+	return c.Prefix
+}
+
+// goroot returns the configured GOROOT, falling back to the GOROOT
+// environment variable, and finally the GOROOT the bot was built with.
+func (c configuration) goroot() string {
+	if c.GOROOT != "" {
+		return c.GOROOT
+	}
+	if env := os.Getenv("GOROOT"); env != "" {
+		return env
+	}
+	return runtime.GOROOT()
+}
+
+// proxy returns the configured module proxies, falling back to the GOPROXY
+// environment variable.
+func (c configuration) proxy() string {
+	if c.Proxy != "" {
+		return c.Proxy
+	}
+	return os.Getenv("GOPROXY")
+}
+
+// modcache returns the configured module cache, falling back to the
+// GOMODCACHE environment variable, and finally the module cache in GOPATH.
+func (c configuration) modcache() string {
+	if c.ModCache != "" {
+		return c.ModCache
+	}
+	if env := os.Getenv("GOMODCACHE"); env != "" {
+		return env
+	}
+	gopath, _, _ := strings.Cut(build.Default.GOPATH, string(filepath.ListSeparator))
+	return filepath.Join(gopath, "pkg", "mod")
+}
+
+// cachedir returns the configured package cache directory.
+func (c configuration) cachedir() string {
+	if c.CacheDir != "" {
+		return c.CacheDir
+	}
+	return "cache"
+}
+
+// statedir returns the directory that the state of the bot, such as the
+// module index and usage counts, is saved in. It is kept in the package cache
+// directory, but the cache only reads packages from files at its top level.
+func (c configuration) statedir() string {
+	return filepath.Join(c.cachedir(), "state")
+}
+
+// cacheSize returns the maximum size of the package cache in bytes, or 0 if
+// it is not limited.
+func (c configuration) cacheSize() (int64, error) {
+	if c.CacheSize == "" {
+		return 0, nil
+	}
+	size, err := humanize.ParseBytes(c.CacheSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cache size: %w", err)
+	}
+	return int64(size), nil
+}
+
+// missingTTLs returns how long modules and symbols that were not found are
+// remembered.
+func (c configuration) missingTTLs() (module, symbol time.Duration, err error) {
+	if module, err = parseDuration("missingmodulettl", c.MissingModuleTTL, missingTTL); err != nil {
+		return 0, 0, err
+	}
+	if symbol, err = parseDuration("missingsymbolttl", c.MissingSymbolTTL, missingTTL); err != nil {
+		return 0, 0, err
+	}
+	return module, symbol, nil
+}
+
+// prewarmInterval returns the time between the searches of prewarmed
+// packages, or 0 if prewarming is disabled.
+func (c configuration) prewarmInterval() (time.Duration, error) {
+	return parseDuration("prewarminterval", c.PrewarmInterval, prewarmInterval)
+}
+
+// parseDuration parses the duration of the named option, returning def if it
+// is empty.
+func parseDuration(name, s string, def time.Duration) (time.Duration, error) {
+	if s == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return d, nil
+}
+
 func saveConfig(config configuration) error {
 	f, err := os.OpenFile("config.json", os.O_WRONLY|os.O_TRUNC, 0o644)
 	if err != nil {
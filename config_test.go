@@ -40,6 +40,9 @@ func TestConfigFromBytes(t *testing.T) {
 	input := []byte(`
 {
 	"prefix": "dr.",
+	"prefixes": {
+		"42": "!"
+	},
 	"permissions": {
 		"docs": [
 			"1337"
@@ -57,7 +60,8 @@ func TestConfigFromBytes(t *testing.T) {
 	assert.NoError(t, err)
 
 	expected := configuration{
-		Prefix: "dr.",
+		Prefix:   "dr.",
+		Prefixes: map[discord.GuildID]string{42: "!"},
 		Permissions: commandPermissions{
 			Docs: map[discord.Snowflake]struct{}{
 				1337: {},
@@ -73,4 +77,6 @@ func TestConfigFromBytes(t *testing.T) {
 	}
 
 	assert.Equal(t, expected, config)
+	assert.Equal(t, "!", config.prefix(42))
+	assert.Equal(t, "dr.", config.prefix(43))
 }
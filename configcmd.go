@@ -9,7 +9,6 @@ import (
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
-	"github.com/hhhapz/doc"
 )
 
 func (b *botState) handleConfig(e *gateway.InteractionCreateEvent, d *discord.CommandInteraction) {
@@ -67,14 +66,11 @@ block:
 			lower := strings.ToLower(cmd.Options[0].String())
 
 			var items []string
-			b.searcher.WithCache(func(cache map[string]*doc.CachedPackage) {
-				for item := range cache {
-					if strings.Contains(strings.ToLower(item), lower) {
-						delete(cache, item)
-						items = append(items, "- "+item)
-					}
-				}
-			})
+			for _, item := range b.searcher.remove(func(module string, _ time.Time) bool {
+				return strings.Contains(strings.ToLower(module), lower)
+			}) {
+				items = append(items, "- "+item)
+			}
 
 			list := strings.Join(items, "\n")
 			if len(list) > 4000 {
@@ -93,14 +89,9 @@ block:
 
 		case "prune":
 			var items []string
-			b.searcher.WithCache(func(cache map[string]*doc.CachedPackage) {
-				for k, cp := range cache {
-					if time.Since(cp.Created) > time.Hour*24 { // removed stuff not used in over 24 hours
-						delete(cache, k)
-						items = append(items, "- "+k)
-					}
-				}
-			})
+			for _, item := range b.searcher.prune(time.Hour * 24) { // removed stuff not used in over 24 hours
+				items = append(items, "- "+item)
+			}
 
 			list := strings.Join(items, "\n")
 			if len(list) > 4000 {
@@ -116,6 +107,58 @@ block:
 					len(items), list),
 				Color: accentColor,
 			}
+
+		case "missing":
+			embed = missingList("Missing modules and symbols",
+				b.missingModules.keys(), b.missingSymbols.keys())
+
+		case "forget":
+			var lower string
+			if len(cmd.Options) > 0 {
+				lower = strings.ToLower(cmd.Options[0].String())
+			}
+			match := func(key string) bool {
+				return strings.Contains(strings.ToLower(key), lower)
+			}
+
+			embed = missingList("Forgot modules and symbols",
+				b.missingModules.clear(match), b.missingSymbols.clear(match))
+		}
+
+	case "prefix":
+		switch cmd.Name {
+		case "set":
+			prefix := cmd.Options[0].String()
+
+			if prefix == "" || len(prefix) > 10 || strings.ContainsAny(prefix, " \n\t`") {
+				embed = failEmbed("Error", "Your prefix must be 1 to 10 characters, without spaces or backticks.")
+				break block
+			}
+
+			b.cfg.Prefixes[e.GuildID] = prefix
+			embed = discord.Embed{
+				Title:       "Success",
+				Description: fmt.Sprintf("Text commands in this server now use the `%s` prefix, such as `%[1]sdocs fmt`.", prefix),
+				Color:       accentColor,
+			}
+		case "disable":
+			b.cfg.Prefixes[e.GuildID] = ""
+			embed = discord.Embed{
+				Title:       "Success",
+				Description: "Text commands are now disabled in this server.",
+				Color:       accentColor,
+			}
+		case "reset":
+			delete(b.cfg.Prefixes, e.GuildID)
+			description := "Text commands in this server are now disabled, as no default prefix is configured."
+			if b.cfg.Prefix != "" {
+				description = fmt.Sprintf("Text commands in this server now use the default `%s` prefix.", b.cfg.Prefix)
+			}
+			embed = discord.Embed{
+				Title:       "Success",
+				Description: description,
+				Color:       accentColor,
+			}
 		}
 
 	case "alias":
@@ -166,6 +209,35 @@ block:
 	}
 }
 
+// missingList lists the modules and symbols that are remembered as not found.
+func missingList(title string, modules, symbols []string) discord.Embed {
+	list := func(keys []string) string {
+		if len(keys) == 0 {
+			return "(empty)"
+		}
+		list := "- " + strings.Join(keys, "\n- ")
+		if len(list) > 1000 {
+			list = list[:900] + "..."
+		}
+		return list
+	}
+
+	return discord.Embed{
+		Title: title,
+		Fields: []discord.EmbedField{
+			{
+				Name:  fmt.Sprintf("Modules (%d)", len(modules)),
+				Value: "```fix\n" + list(modules) + "```",
+			},
+			{
+				Name:  fmt.Sprintf("Symbols (%d)", len(symbols)),
+				Value: "```fix\n" + list(symbols) + "```",
+			},
+		},
+		Color: accentColor,
+	}
+}
+
 func (b *botState) canIgnore(guild discord.GuildID, user discord.Snowflake) bool {
 	m, err := b.state.Member(guild, discord.UserID(user))
 	if err != nil {
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+var (
+	// qualifiedRe matches a qualified symbol, such as io.ReadAll,
+	// net/http.Request or [os.File.Close].
+	qualifiedRe = regexp.MustCompile(`\b((?:[a-z][\w.-]*/)*[a-z]\w*\.[A-Z]\w*(?:\.[A-Z]\w*)?)\b`)
+	// useRe matches a replacement in the same package, such as "Use NewFoo
+	// instead".
+	useRe = regexp.MustCompile(`(?i:\buse)\s+\[?([A-Z]\w*(?:\.[A-Z]\w*)?)\b`)
+)
+
+// deprecation returns the text of the "Deprecated:" paragraph of a doc
+// comment, without the prefix, or an empty string if there is none.
+func deprecation(comment string) string {
+	for _, para := range strings.Split(comment, "\n\n") {
+		para = strings.TrimSpace(para)
+		if text, ok := strings.CutPrefix(para, "Deprecated:"); ok {
+			return strings.Join(strings.Fields(text), " ")
+		}
+	}
+	return ""
+}
+
+// replacement finds the symbol suggested as the replacement in a deprecation
+// notice, and returns its docs query. Unqualified symbols are resolved in pkg.
+func replacement(pkg, text string) string {
+	if m := qualifiedRe.FindStringSubmatch(text); m != nil {
+		return m[1]
+	}
+	if m := useRe.FindStringSubmatch(text); m != nil {
+		return pkg + "." + m[1]
+	}
+	return ""
+}
+
+// replacementURL links to the documentation of a replacement query on
+// pkg.go.dev, applying the standard library aliases to its package.
+func replacementURL(query string) string {
+	dir, base := "", query
+	if i := strings.LastIndex(query, "/"); i != -1 {
+		dir, base = query[:i+1], query[i+1:]
+	}
+	pkg, name, _ := strings.Cut(base, ".")
+	pkg = dir + pkg
+	if full, ok := stdlibAliases[pkg]; ok {
+		pkg = full
+	}
+	return fmt.Sprintf("https://pkg.go.dev/%s#%s", pkg, name)
+}
+
+// commentText returns the text of the doc comment of the symbol.
+func (s symbol) commentText() string {
+	switch s.kind {
+	case kindType:
+		return s.typ.Comment.Text()
+	case kindFunc:
+		return s.fn.Comment.Text()
+	case kindConst, kindVar:
+		return s.v.Comment.Text()
+	case kindMethod:
+		return s.method.Comment.Text()
+	case kindField:
+		return s.field.doc + "\n\n" + s.field.comment
+	}
+	return s.pkg.Overview.Text()
+}
+
+// deprecatedField returns a warning embed field if the symbol is deprecated,
+// with a link to the suggested replacement if there is one.
+func (s symbol) deprecatedField() (discord.EmbedField, bool) {
+	text := deprecation(s.commentText())
+	if text == "" {
+		return discord.EmbedField{}, false
+	}
+
+	const limit = 900
+	if len(text) > limit {
+		cut := strings.LastIndexByte(text[:limit], ' ')
+		if cut == -1 {
+			cut = limit
+		}
+		text = text[:cut] + "..."
+	}
+
+	importPath, _ := s.importPath()
+	if query := replacement(importPath, text); query != "" {
+		text += fmt.Sprintf("\n\nSuggested replacement: [%s](%s)", query, replacementURL(query))
+	}
+	return discord.EmbedField{
+		Name:  "⚠️ Deprecated",
+		Value: text,
+	}, true
+}
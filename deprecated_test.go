@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hhhapz/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecation(t *testing.T) {
+	cases := []struct {
+		name        string
+		comment     string
+		deprecation string
+		replacement string
+	}{
+		{
+			name:        "qualified",
+			comment:     "ReadAll reads from r until an error or EOF.\n\nDeprecated: As of Go 1.16, this function simply calls\nio.ReadAll.",
+			deprecation: "As of Go 1.16, this function simply calls io.ReadAll.",
+			replacement: "io.ReadAll",
+		},
+		{
+			name:        "doc link",
+			comment:     "Deprecated: Use [golang.org/x/text/cases.Title] instead.",
+			deprecation: "Use [golang.org/x/text/cases.Title] instead.",
+			replacement: "golang.org/x/text/cases.Title",
+		},
+		{
+			name:        "same package",
+			comment:     "Deprecated: Use NewEncoder instead.",
+			deprecation: "Use NewEncoder instead.",
+			replacement: "encoding/json.NewEncoder",
+		},
+		{
+			name:        "no replacement",
+			comment:     "Deprecated: this type is unused.",
+			deprecation: "this type is unused.",
+		},
+		{
+			name:    "not deprecated",
+			comment: "Marshal returns the JSON encoding of v.\nIt is not Deprecated: at all.",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			text := deprecation(c.comment)
+			assert.Equal(t, c.deprecation, text)
+			assert.Equal(t, c.replacement, replacement("encoding/json", text))
+		})
+	}
+
+	assert.Equal(t, "https://pkg.go.dev/io#ReadAll", replacementURL("io.ReadAll"))
+	assert.Equal(t, "https://pkg.go.dev/encoding/json#Decoder.Decode", replacementURL("json.Decoder.Decode"))
+	assert.Equal(t, "https://pkg.go.dev/golang.org/x/text/cases#Title", replacementURL("golang.org/x/text/cases.Title"))
+}
+
+func TestPackageOptionsDeprecated(t *testing.T) {
+	pkg := doc.Package{
+		Functions: map[string]doc.Function{
+			"readall":  {Name: "ReadAll", Comment: doc.Comment{doc.Paragraph("Deprecated: Use io.ReadAll.")}},
+			"readfile": {Name: "ReadFile"},
+		},
+	}
+
+	opts := packageOptions("read", pkg)
+	if assert.Len(t, opts, 2) {
+		assert.Equal(t, "ReadFile", opts[0].name)
+		assert.Equal(t, "ReadAll", opts[1].name)
+		assert.True(t, opts[1].deprecated)
+		assert.Equal(t, "ReadAll (func, deprecated)", opts[1].choice())
+	}
+}
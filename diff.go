@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/hhhapz/doc"
+)
+
+const diffUsage = "Usage: `/docs module:diff item:<module> from:<version> [to:<version>]`."
+
+// apiDiff contains the exported API differences between two versions of a
+// package. Every entry is formatted as "<kind> <name>", for example
+// "func NewReader" or "method Reader.Read".
+type apiDiff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+func (d apiDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// diffPackages compares the constants, variables, functions, types and
+// methods of two versions of the same package.
+func diffPackages(from, to doc.Package) apiDiff {
+	var d apiDiff
+	compare := func(kind string, old, cur map[string]string) {
+		for name, sig := range cur {
+			oldSig, ok := old[name]
+			switch {
+			case !ok:
+				d.Added = append(d.Added, kind+" "+name)
+			case normalizeSignature(oldSig) != normalizeSignature(sig):
+				d.Changed = append(d.Changed, kind+" "+name)
+			}
+		}
+		for name := range old {
+			if _, ok := cur[name]; !ok {
+				d.Removed = append(d.Removed, kind+" "+name)
+			}
+		}
+	}
+
+	compare("const", variableSignatures(from.ConstantMap), variableSignatures(to.ConstantMap))
+	compare("var", variableSignatures(from.VariableMap), variableSignatures(to.VariableMap))
+	compare("func", functionSignatures(from.Functions), functionSignatures(to.Functions))
+	compare("type", typeSignatures(from.Types), typeSignatures(to.Types))
+	compare("method", methodSignatures(from.Types), methodSignatures(to.Types))
+
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Strings(d.Changed)
+	return d
+}
+
+// variableSignatures maps constants or variables to their own line in the
+// declaration, so that a change to another value in the same block is not
+// reported as a change.
+func variableSignatures(vars map[string]doc.Variable) map[string]string {
+	sigs := make(map[string]string, len(vars))
+	for _, v := range vars {
+		sigs[v.Name] = declLine(v.Signature, v.Name)
+	}
+	return sigs
+}
+
+func functionSignatures(fns map[string]doc.Function) map[string]string {
+	sigs := make(map[string]string, len(fns))
+	for _, fn := range fns {
+		sigs[fn.Name] = fn.Signature
+	}
+	return sigs
+}
+
+func typeSignatures(types map[string]doc.Type) map[string]string {
+	sigs := make(map[string]string, len(types))
+	for _, typ := range types {
+		sigs[typ.Name] = typ.Signature
+	}
+	return sigs
+}
+
+func methodSignatures(types map[string]doc.Type) map[string]string {
+	sigs := map[string]string{}
+	for _, typ := range types {
+		for _, method := range typ.Methods {
+			sigs[typ.Name+"."+method.Name] = method.Signature
+		}
+	}
+	return sigs
+}
+
+// declLine returns the line of a const or var block that declares name. If no
+// such line is found, the full declaration is returned.
+func declLine(sig, name string) string {
+	for _, line := range strings.Split(sig, "\n") {
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ',' || r == '='
+		})
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "const" || fields[0] == "var" {
+			fields = fields[1:]
+		}
+		for _, f := range fields {
+			if f == name {
+				return line
+			}
+			if strings.HasPrefix(f, "//") {
+				break
+			}
+		}
+	}
+	return sig
+}
+
+// normalizeSignature removes comments and formatting differences from a
+// declaration, so that only changes to the declaration itself are compared.
+func normalizeSignature(sig string) string {
+	lines := strings.Split(sig, "\n")
+	for i, line := range lines {
+		if idx := strings.Index(line, "//"); idx != -1 {
+			lines[i] = line[:idx]
+		}
+	}
+	return strings.Join(strings.Fields(strings.Join(lines, " ")), " ")
+}
+
+func (b *botState) diff(user discord.User, query, from, to string) discord.Embed {
+	if query == "" || from == "" {
+		return failEmbed("Error", diffUsage)
+	}
+
+	module, _ := parseQuery(query)
+	module, _ = splitVersion(b.modulePath(module))
+
+	fromName := module + "@" + from
+	toName := module
+	if to != "" {
+		toName += "@" + to
+	} else {
+		to = "latest"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), searchTimeout)
+	defer cancel()
+
+	fromPkg, err := b.searcher.Search(ctx, fromName)
+	if err != nil {
+		log.Printf("Package request by %s(%q) failed: %v", user.Tag(), fromName, err)
+		lerr := searchError(err, fromName)
+		return failEmbed(lerr.title, lerr.msg)
+	}
+	toPkg, err := b.searcher.Search(ctx, toName)
+	if err != nil {
+		log.Printf("Package request by %s(%q) failed: %v", user.Tag(), toName, err)
+		lerr := searchError(err, toName)
+		return failEmbed(lerr.title, lerr.msg)
+	}
+
+	d := diffPackages(fromPkg, toPkg)
+
+	desc := "*No API changes found.*"
+	if !d.empty() {
+		var sb strings.Builder
+		// Every section is given an equal share of the description, any
+		// remaining entries are summarized.
+		limit := 3800 / 3
+		diffSection(&sb, "Added", "diff", "+ ", d.Added, limit)
+		diffSection(&sb, "Removed", "diff", "- ", d.Removed, limit)
+		diffSection(&sb, "Changed", "fix", "~ ", d.Changed, limit)
+		desc = sb.String()
+	}
+
+	return discord.Embed{
+		Title:       fmt.Sprintf("Diff: %s %s → %s", module, from, to),
+		URL:         fmt.Sprintf("https://pkg.go.dev/%s?tab=versions", module),
+		Description: desc,
+		Footer: &discord.EmbedFooter{
+			Text: fmt.Sprintf("%d added, %d removed, %d changed", len(d.Added), len(d.Removed), len(d.Changed)),
+		},
+		Color: accentColor,
+	}
+}
+
+func diffSection(sb *strings.Builder, title, lang, prefix string, items []string, limit int) {
+	if len(items) == 0 {
+		return
+	}
+
+	fmt.Fprintf(sb, "**%s (%d):**```%s\n", title, len(items), lang)
+	length := 0
+	for i, item := range items {
+		line := prefix + item + "\n"
+		if length+len(line) > limit {
+			fmt.Fprintf(sb, "... and %d more\n", len(items)-i)
+			break
+		}
+		length += len(line)
+		sb.WriteString(line)
+	}
+	sb.WriteString("```")
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hhhapz/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffPackages(t *testing.T) {
+	consts := "const (\n\tA = 1\n\tB = 2 // B is two.\n)"
+	from := doc.Package{
+		ConstantMap: map[string]doc.Variable{
+			"a": {Name: "A", Signature: consts},
+			"b": {Name: "B", Signature: consts},
+		},
+		VariableMap: map[string]doc.Variable{
+			"errold": {Name: "ErrOld", Signature: `var ErrOld = errors.New("old")`},
+		},
+		Functions: map[string]doc.Function{
+			"new":   {Name: "New", Signature: "func New() *Client"},
+			"parse": {Name: "Parse", Signature: "func Parse(s string) error"},
+		},
+		Types: map[string]doc.Type{
+			"client": {
+				Name:      "Client",
+				Signature: "type Client struct {\n\t// contains filtered or unexported fields\n}",
+				Methods: map[string]doc.Method{
+					"do": {For: "Client", Function: doc.Function{Name: "Do", Signature: "func (c *Client) Do() error"}},
+				},
+			},
+		},
+	}
+	to := doc.Package{
+		ConstantMap: map[string]doc.Variable{
+			"a": {Name: "A", Signature: "const (\n\tA = 1\n\tB = 3 // B is three.\n\tC = 4\n)"},
+			"b": {Name: "B", Signature: "const (\n\tA = 1\n\tB = 3 // B is three.\n\tC = 4\n)"},
+			"c": {Name: "C", Signature: "const (\n\tA = 1\n\tB = 3 // B is three.\n\tC = 4\n)"},
+		},
+		Functions: map[string]doc.Function{
+			"new":   {Name: "New", Signature: "func New()   *Client"},
+			"parse": {Name: "Parse", Signature: "func Parse(s string) (int, error)"},
+		},
+		Types: map[string]doc.Type{
+			"client": {
+				Name:      "Client",
+				Signature: "type Client struct {\n\t// unexported fields are hidden\n}",
+				Methods: map[string]doc.Method{
+					"do":    {For: "Client", Function: doc.Function{Name: "Do", Signature: "func (c *Client) Do() error"}},
+					"close": {For: "Client", Function: doc.Function{Name: "Close", Signature: "func (c *Client) Close() error"}},
+				},
+			},
+		},
+	}
+
+	d := diffPackages(from, to)
+	assert.Equal(t, []string{"const C", "method Client.Close"}, d.Added)
+	assert.Equal(t, []string{"var ErrOld"}, d.Removed)
+	assert.Equal(t, []string{"const B", "func Parse"}, d.Changed)
+	assert.True(t, diffPackages(from, from).empty())
+}
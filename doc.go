@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"path"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -17,11 +20,30 @@ import (
 )
 
 const (
-	searchErr      = "Could not find package with the name of `%s`."
-	notFound       = "Could not find type or function `%s` in package `%s`."
-	methodNotFound = "Could not find method `%s` for type `%s` in package `%s`."
-	notOwner       = "Only the message sender can do this."
-	cannotExpand   = "You cannot expand this embed."
+	searchErr       = "Could not find package with the name of `%s`."
+	timeoutErr      = "Upstream timed out while loading `%s`, please try again in a moment."
+	notFound        = "Could not find type or function `%s` in package `%s`."
+	methodNotFound  = "Could not find method `%s` for type `%s` in package `%s`."
+	externalEmbeds  = "\nMembers promoted from embedded types of other packages are not resolved: `%s`."
+	ambiguousMember = "`%s` is ambiguous in `%s`, it could be any of `%s`."
+	notOwner        = "Only the message sender can do this."
+	cannotExpand    = "You cannot expand this embed."
+	expired         = "This message has expired, please search again."
+)
+
+const (
+	// searchTimeout is how long a docs query waits for its package. The
+	// response is deferred, so it may take longer than the three seconds
+	// that Discord waits for the response to an interaction.
+	searchTimeout = 10 * time.Second
+	// componentTimeout is how long the response to a component waits for
+	// its package. Unlike commands, most components are not deferred, so
+	// they must be responded to within those three seconds.
+	componentTimeout = 2500 * time.Millisecond
+	// autocompleteTimeout is how long autocomplete waits for a package,
+	// which must respond within those three seconds. The package is still
+	// searched for in the background, so that later choices include it.
+	autocompleteTimeout = 2 * time.Second
 )
 
 type interactionData struct {
@@ -32,6 +54,7 @@ type interactionData struct {
 	channelID discord.ChannelID
 	messageID discord.MessageID
 	query     string
+	goVersion string
 }
 
 var (
@@ -53,9 +76,11 @@ func (b *botState) gcInteractionData() {
 			mu.Unlock()
 
 			if data.token == "" {
-				b.state.EditMessageComplex(data.channelID, data.messageID, api.EditMessageData{
-					Components: &discord.ContainerComponents{},
-				})
+				if data.messageID.IsValid() {
+					b.state.EditMessageComplex(data.channelID, data.messageID, api.EditMessageData{
+						Components: &discord.ContainerComponents{},
+					})
+				}
 				continue
 			}
 
@@ -63,13 +88,10 @@ func (b *botState) gcInteractionData() {
 				Components: &discord.ContainerComponents{},
 			})
 		}
-		b.searcher.WithCache(func(cache map[string]*doc.CachedPackage) {
-			for k, cp := range cache {
-				if time.Since(cp.Created) > time.Hour*72 { // removed stuff not used in over 72 hours
-					delete(cache, k)
-				}
-			}
-		})
+		b.searcher.prune(cacheMaxIdle)
+		if err := b.usage.save(); err != nil {
+			log.Printf("Could not save usage: %v", err)
+		}
 	}
 }
 
@@ -80,26 +102,66 @@ func (b *botState) handleDocs(e *gateway.InteractionCreateEvent, d *discord.Comm
 		return
 	}
 
-	var first, query string
-
-	first = d.Options[0].String()
-	query = first + " " + d.Options[1].String()
-
-	if item := d.Options[1].String(); item == "<pkginfo>" || item == "." {
+	// Both options are optional, so that a symbol can be searched without
+	// naming its package.
+	first, item := d.Options.Find("module").String(), d.Options.Find("item").String()
+	query := strings.TrimSpace(first + " " + item)
+	if item == "<pkginfo>" || item == "." {
 		query = first
 	}
+	if query == "" {
+		first = "help"
+	}
+	goVersion := d.Options.Find("version").String()
 
 	log.Printf("%s used docs(%q)", e.User.Tag(), query)
 
+	ctx, cancel := context.WithTimeout(context.Background(), searchTimeout)
+	defer cancel()
+
 	var embed discord.Embed
-	var internal, more bool
+	var internal bool
+	var component discord.InteractiveComponent = buttonComponent(e.ID.String())
+	var components discord.ContainerComponents
 	switch first {
 	case "?", "help", "usage":
 		embed, internal = helpEmbed(), true
 	case "alias", "aliases":
 		embed, internal = aliasList(b.cfg.Aliases), true
+	case "diff":
+		from, to := d.Options.Find("from").String(), d.Options.Find("to").String()
+		embed = b.diff(*e.User, item, from, to)
+	case "search":
+		std, _ := d.Options.Find("stdlib").BoolValue()
+		embed, components = b.search(ctx, *e.User, e.ID.String(), d.Options.Find("text").String(), std)
 	default:
-		embed, more = b.docs(*e.User, query, false)
+		var candidates []indexedSymbol
+		if query, candidates = b.resolveSymbol(ctx, query); len(candidates) > 0 {
+			embed, components = symbolPicker(e.ID.String(), query, candidates)
+			break
+		}
+
+		if goVersion != "" {
+			var ok bool
+			if goVersion, ok = parseGoVersion(goVersion); !ok {
+				embed = failEmbed("Error", fmt.Sprintf(invalidGoVersion, goVersion))
+				break
+			}
+		}
+
+		var sym *symbol
+		var more bool
+		embed, sym, more = b.docs(ctx, *e.User, query)
+		if !strings.HasPrefix(embed.Title, "Error") {
+			b.recordUsage(query)
+		}
+		b.addVersionWarning(sym, goVersion, &embed)
+		components = docsComponents(e.ID.String(), sym, false, more)
+	}
+	if components == nil {
+		components = discord.ContainerComponents{
+			&discord.ActionRowComponent{component},
+		}
 	}
 
 	if internal || strings.HasPrefix(embed.Title, "Error") {
@@ -119,27 +181,18 @@ func (b *botState) handleDocs(e *gateway.InteractionCreateEvent, d *discord.Comm
 
 	mu.Lock()
 	interactionMap[e.ID.String()] = &interactionData{
-		id:      e.ID.String(),
-		created: time.Now(),
-		token:   e.Token,
-		userID:  e.User.ID,
-		query:   query,
+		id:        e.ID.String(),
+		created:   time.Now(),
+		token:     e.Token,
+		userID:    e.User.ID,
+		query:     query,
+		goVersion: goVersion,
 	}
 	mu.Unlock()
 
-	// If more is true, there is more content that was omitted in the embed.
-	// If more is false, there is no more content, and the expand option
-	// becomes redundant.
-	var component discord.InteractiveComponent = selectComponent(e.ID.String(), false)
-	if !more {
-		component = buttonComponent(e.ID.String())
-	}
-
 	if _, err := b.state.EditInteractionResponse(e.AppID, e.Token, api.EditInteractionResponseData{
-		Embeds: &[]discord.Embed{embed},
-		Components: &discord.ContainerComponents{
-			&discord.ActionRowComponent{component},
-		},
+		Embeds:     &[]discord.Embed{embed},
+		Components: &components,
 	}); err != nil {
 		log.Printf("could not send interaction callback, %v", err)
 		return
@@ -156,25 +209,55 @@ func (b *botState) handleDocsText(m *gateway.MessageCreateEvent, queries []textQ
 		log.Printf("%s used docs(%v) text version", m.Author.Tag(), queries)
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), searchTimeout)
+	defer cancel()
+
 	var internal []discord.Embed
 	var embeds []discord.Embed
+	var failed []discord.Embed
 	var more []bool
-	for _, q := range queries {
+	var syms []*symbol
+	var picker discord.ContainerComponents
+	for i := range queries {
+		q := &queries[i]
 		switch q.query {
 		case "?", "help", "usage":
 			internal = append(internal, helpEmbed())
 		case "alias", "aliases":
 			internal = append(internal, aliasList(b.cfg.Aliases))
 		default:
-			embed, m := b.docs(m.Author, q.query, false)
+			// The picker is only shown for a single query, otherwise the best
+			// candidate is used.
+			query, candidates := b.resolveSymbol(ctx, q.query)
+			if len(candidates) > 0 && len(queries) == 1 {
+				var embed discord.Embed
+				embed, picker = symbolPicker(m.ID.String(), q.query, candidates)
+				embeds = append(embeds, embed)
+				more = append(more, false)
+				syms = append(syms, nil)
+				continue
+			}
+			if len(candidates) > 0 {
+				query = candidates[0].query()
+			}
+			q.query = query
+
+			embed, sym, m := b.docs(ctx, m.Author, q.query)
 			if strings.HasPrefix(embed.Title, "Error") {
+				// Only text commands show errors, as other queries
+				// may not be meant for the bot.
+				if q.source == "prefix" {
+					failed = append(failed, embed)
+				}
 				continue
 			}
 			if strings.HasPrefix(embed.Title, "Package") && q.source == "urlre" {
 				continue
 			}
+			b.recordUsage(q.query)
 			embeds = append(embeds, embed)
 			more = append(more, m)
+			syms = append(syms, sym)
 		}
 	}
 
@@ -184,53 +267,23 @@ func (b *botState) handleDocsText(m *gateway.MessageCreateEvent, queries []textQ
 	}
 
 	if len(embeds) == 0 {
+		if len(failed) > 0 {
+			b.replyText(m, queries[0].query, failed, discord.ContainerComponents{})
+		}
 		return
 	}
 
-	var component discord.InteractiveComponent = selectComponent(m.ID.String(), false)
-	if len(embeds) == 1 && more[0] {
-		component = buttonComponent(m.ID.String())
+	components := discord.ContainerComponents{
+		&discord.ActionRowComponent{selectComponent(m.ID.String(), false, true)},
 	}
-
-	data, ok := interactionMap[m.ID.String()]
-	if ok {
-		mu.Lock()
-		interactionMap[m.ID.String()].query = queries[0].query
-		mu.Unlock()
-
-		b.state.EditMessageComplex(m.ChannelID, data.messageID, api.EditMessageData{
-			Embeds: &embeds,
-			Components: &discord.ContainerComponents{
-				&discord.ActionRowComponent{component},
-			},
-		})
-		return
+	if len(embeds) == 1 {
+		components = docsComponents(m.ID.String(), syms[0], false, more[0])
 	}
-
-	mu.Lock()
-	interactionMap[m.ID.String()] = &interactionData{
-		id:      m.ID.String(),
-		created: time.Now(),
-		userID:  m.Author.ID,
-		query:   queries[0].query,
+	if picker != nil {
+		components = picker
 	}
-	mu.Unlock()
 
-	msg, err := b.state.SendMessageComplex(m.ChannelID, api.SendMessageData{
-		Components: discord.ContainerComponents{
-			&discord.ActionRowComponent{component},
-		},
-		Embeds: embeds,
-	})
-	if err != nil {
-		delete(interactionMap, m.ID.String())
-		return
-	}
-
-	mu.Lock()
-	interactionMap[m.ID.String()].channelID = msg.ChannelID
-	interactionMap[m.ID.String()].messageID = msg.ID
-	mu.Unlock()
+	b.replyText(m, queries[0].query, embeds, components)
 }
 
 func (b *botState) handleDocsComponent(e *gateway.InteractionCreateEvent, data *interactionData) {
@@ -270,49 +323,65 @@ func (b *botState) handleDocsComponent(e *gateway.InteractionCreateEvent, data *
 
 	log.Printf("%s used docs component(%q)", e.User.Tag(), action)
 
+	ctx, cancel := context.WithTimeout(context.Background(), componentTimeout)
+	defer cancel()
+
 	switch action {
 	case "minimize":
-		embed, _ := b.docs(*e.User, data.query, false)
+		embed, sym, more := b.interactionDocs(ctx, *e.User, data)
 		embeds = append(embeds, embed)
-		components = &discord.ContainerComponents{
-			&discord.ActionRowComponent{
-				selectComponent(data.id, false),
-			},
-		}
+		c := docsComponents(data.id, sym, false, more)
+		components = &c
 
 	// Admin or privileged only.
 	// (Only check admin here to reduce total API calls).
 	// If not privileged, send ephemeral instead.
 	case "expand.all":
-		embed, _ := b.docs(*e.User, data.query, true)
-		components = &discord.ContainerComponents{
-			&discord.ActionRowComponent{
-				selectComponent(data.id, true),
-			},
-		}
+		embed, sym, pages := b.interactionPage(ctx, *e.User, data, 0)
+		components = pageComponents(data, sym, false, 0, pages)
 
 		if !hasPerm() {
 			embed = failEmbed("Error", "You do not have the permission to do this.")
 		}
 		embeds = append(embeds, embed)
 	case "expand":
-		embed, _ := b.docs(*e.User, data.query, true)
-		embeds = append(embeds, embed)
-		components = &discord.ContainerComponents{
-			&discord.ActionRowComponent{
-				selectComponent(data.id, true),
-			},
-		}
+		embed, sym, pages := b.interactionPage(ctx, *e.User, data, 0)
 
 		_ = b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
 			Type: api.MessageInteractionWithSource,
 			Data: &api.InteractionResponseData{
-				Flags:  discord.EphemeralMessage,
-				Embeds: &[]discord.Embed{embed},
+				Flags:      discord.EphemeralMessage,
+				Embeds:     &[]discord.Embed{embed},
+				Components: pageComponents(data, sym, true, 0, pages),
 			},
 		})
 		return
 
+	// Examples are shown privately, so anyone can view them.
+	case "examples":
+		b.handleExamples(e, data)
+		return
+
+	case "source":
+		b.handleSource(e, data)
+		return
+
+	case "parent":
+		sym, err := b.lookup(ctx, *e.User, data.query)
+		if err != nil {
+			return
+		}
+		parent, ok := parentPackage(sym)
+		if !ok {
+			return
+		}
+		if e.User.ID != data.userID {
+			b.respondError(e, notOwner)
+			return
+		}
+		b.navigate(ctx, e, data, parent)
+		return
+
 	case "hide":
 		components = &discord.ContainerComponents{}
 		for _, embed := range e.Message.Embeds {
@@ -351,6 +420,80 @@ func (b *botState) handleDocsComponent(e *gateway.InteractionCreateEvent, data *
 	b.state.RespondInteraction(e.ID, e.Token, resp)
 }
 
+// handleDocsSubcomponent handles components on docs messages that are not the
+// main actions menu. Their IDs are formatted as "docs.<action>.<args>".
+func (b *botState) handleDocsSubcomponent(e *gateway.InteractionCreateEvent, data discord.ComponentInteraction, cmd string) {
+	action, args, _ := strings.Cut(cmd, ".")
+	switch action {
+	case "example":
+		b.handleExampleComponent(e, data, args)
+	case "source":
+		b.handleSourceComponent(e, args)
+	case "pick":
+		b.handlePickComponent(e, data, args)
+	case "page":
+		b.handlePageComponent(e, args)
+	case "browse":
+		b.handleBrowseComponent(e, data, args)
+	}
+}
+
+// pageComponents returns the components of a page of expanded docs. Private
+// messages only have the page buttons, while public messages also keep the
+// actions menu, so that they can be minimized again.
+func pageComponents(data *interactionData, sym *symbol, ephemeral bool, page, pages int) *discord.ContainerComponents {
+	var components discord.ContainerComponents
+	if !ephemeral {
+		components = docsComponents(data.id, sym, true, false)
+	}
+	if pages > 1 {
+		// Discord allows at most five rows, so the last browse menu makes room
+		// for the page buttons.
+		if len(components) == 5 {
+			components = components[:4]
+		}
+		components = append(components, pageButtons("docs.page."+data.id+".", page, pages))
+	}
+	return &components
+}
+
+// handlePageComponent handles the page buttons of expanded docs. The command
+// is formatted as "<id>.<page>".
+func (b *botState) handlePageComponent(e *gateway.InteractionCreateEvent, cmd string) {
+	id, pageStr, _ := strings.Cut(cmd, ".")
+	page, _ := strconv.Atoi(pageStr)
+
+	mu.Lock()
+	data, ok := interactionMap[id]
+	mu.Unlock()
+
+	ephemeral := e.Message != nil && e.Message.Flags&discord.EphemeralMessage != 0
+	switch {
+	case !ok:
+		b.respondError(e, expired)
+		return
+	// Anyone can page through their own private copy, but only the sender can
+	// change the page of a public message.
+	case !ephemeral && e.GuildID != discord.NullGuildID && e.User.ID != data.userID:
+		b.respondError(e, notOwner)
+		return
+	}
+
+	log.Printf("%s used docs page(%q, %d)", e.User.Tag(), data.query, page)
+
+	ctx, cancel := context.WithTimeout(context.Background(), componentTimeout)
+	defer cancel()
+
+	embed, sym, pages := b.interactionPage(ctx, *e.User, data, page)
+	b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+		Type: api.UpdateMessage,
+		Data: &api.InteractionResponseData{
+			Embeds:     &[]discord.Embed{embed},
+			Components: pageComponents(data, sym, ephemeral, min(max(page, 0), pages-1), pages),
+		},
+	})
+}
+
 func (b *botState) handleDocsComplete(e *gateway.InteractionCreateEvent, d *discord.AutocompleteInteraction) {
 	values := map[string]string{}
 	var focused string
@@ -375,11 +518,29 @@ func (b *botState) handleDocsComplete(e *gateway.InteractionCreateEvent, d *disc
 	if focused != "module" {
 		switch {
 		case query == "":
-			add(item, item)
+			// Without a module, symbols are suggested from all packages.
+			for _, s := range rankSymbols(item, b.symbolEntries()) {
+				add(s.query(), s.query())
+			}
+			if len(opts) == 0 {
+				add(item, item)
+			}
 		case query == "help", query == "alias":
 			add(query, query)
+		case query == "diff":
+			ranks := b.packageCache(item)
+			sort.Sort(ranks)
+
+			if len(ranks) > 25 {
+				ranks = ranks[:25]
+			}
+
+			for _, item := range ranks {
+				add(item.Target, item.Target)
+			}
 		default:
-			module, parts := parseQuery(query + " " + item)
+			module, parts := splitQuery(query + " " + item)
+			module, version := splitVersion(module)
 
 			var pkg doc.Package
 			var ok bool
@@ -401,9 +562,14 @@ func (b *botState) handleDocsComplete(e *gateway.InteractionCreateEvent, d *disc
 					ok = true
 				}
 			}
+			if version != "" {
+				module += "@" + version
+			}
 
 			if ok {
-				pkg, _ = b.searcher.Search(context.Background(), module)
+				ctx, cancel := context.WithTimeout(context.Background(), autocompleteTimeout)
+				pkg, _ = b.searcher.Search(ctx, module)
+				cancel()
 			} else {
 				b.searcher.WithCache(func(cache map[string]*doc.CachedPackage) {
 					if cpkg, ok := cache[module]; ok {
@@ -412,15 +578,9 @@ func (b *botState) handleDocsComplete(e *gateway.InteractionCreateEvent, d *disc
 				})
 			}
 
-			ranks := packageOptions(parts, pkg)
-			sort.Sort(ranks)
-
-			if len(ranks) > 25 {
-				ranks = ranks[:25]
-			}
-
-			for _, item := range ranks {
-				add(item.Target, item.Target)
+			options := packageOptions(strings.Join(parts, "."), pkg)
+			for _, opt := range options[:min(len(options), 25)] {
+				add(opt.choice(), opt.name)
 			}
 		}
 	} else {
@@ -428,8 +588,14 @@ func (b *botState) handleDocsComplete(e *gateway.InteractionCreateEvent, d *disc
 		case "":
 			add("help", "help")
 			add("alias", "alias")
+			add("diff", "diff")
+			add("search", "search")
 		case "ali", "alias", "aliases":
 			add("alias", "alias")
+		case "dif", "diff":
+			add("diff", "diff")
+		case "sea", "search":
+			add("search", "search")
 		case "hel", "help", "info", "?":
 			add("help", "help")
 		}
@@ -445,6 +611,18 @@ func (b *botState) handleDocsComplete(e *gateway.InteractionCreateEvent, d *disc
 		return
 	}
 
+	// Versions are suggested until the symbol is typed after them.
+	if _, v, ok := strings.Cut(query, "@"); ok && !strings.Contains(v, " ") {
+		opts = b.versionChoices(query)
+		b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+			Type: api.AutocompleteResult,
+			Data: &api.InteractionResponseData{
+				Choices: &opts,
+			},
+		})
+		return
+	}
+
 	var split []string
 	var module string
 
@@ -477,85 +655,344 @@ func (b *botState) handleDocsComplete(e *gateway.InteractionCreateEvent, d *disc
 	})
 }
 
-func (b *botState) docs(user discord.User, query string, full bool) (discord.Embed, bool) {
+// symbolKind is the kind of item a docs query resolved to.
+type symbolKind int
+
+const (
+	kindPackage symbolKind = iota
+	kindType
+	kindFunc
+	kindConst
+	kindVar
+	kindMethod
+	kindField
+)
+
+// symbol is a resolved docs query. Depending on kind, one of typ, fn, v,
+// method or field is set. For methods and fields, typ is the type that
+// declares them, and via is set if they are promoted from an embedded field.
+type symbol struct {
+	pkg  doc.Package
+	kind symbolKind
+
+	typ    doc.Type
+	fn     doc.Function
+	v      doc.Variable
+	method doc.Method
+	field  structField
+	via    []string
+}
+
+// title returns the name of the symbol, as displayed in embed titles.
+func (s symbol) title() string {
+	switch s.kind {
+	case kindType:
+		return fmt.Sprintf("%s: %s", s.pkg.Name, s.typ.Name)
+	case kindFunc:
+		return fmt.Sprintf("%s: %s", s.pkg.Name, s.fn.Name)
+	case kindConst, kindVar:
+		return fmt.Sprintf("%s: %s", s.pkg.Name, s.v.Name)
+	case kindMethod:
+		return fmt.Sprintf("%s: %s.%s", s.pkg.Name, s.method.For, s.method.Name)
+	case kindField:
+		return fmt.Sprintf("%s: %s.%s", s.pkg.Name, s.typ.Name, s.field.name)
+	}
+	return "Package " + s.pkg.Name
+}
+
+// examples returns the examples that belong to the symbol. For packages, the
+// package examples are returned.
+func (s symbol) examples() []doc.Example {
+	switch s.kind {
+	case kindType:
+		return s.typ.Examples
+	case kindFunc:
+		return s.fn.Examples
+	case kindMethod:
+		return s.method.Examples
+	case kindPackage:
+		var examples []doc.Example
+		for _, ex := range s.pkg.Examples {
+			if ex.Name == "package" || strings.HasPrefix(ex.Name, "package-") {
+				examples = append(examples, ex)
+			}
+		}
+		return examples
+	}
+	return nil
+}
+
+// lookupError is returned by lookup when a query could not be resolved. It
+// is displayed to the user with failEmbed.
+type lookupError struct {
+	title string
+	msg   string
+}
+
+func (err lookupError) Error() string {
+	return err.msg
+}
+
+// searchError returns the error shown to the user when the package of module
+// could not be searched.
+func searchError(err error, module string) lookupError {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return lookupError{"Error: Timed Out", fmt.Sprintf(timeoutErr, module)}
+	}
+	return lookupError{"Error", fmt.Sprintf(searchErr, module)}
+}
+
+// lookup resolves a docs query to the package, and the item in the package
+// that it refers to. The package is searched until ctx is done, whose deadline
+// depends on how long the caller has to respond.
+func (b *botState) lookup(ctx context.Context, user discord.User, query string) (symbol, error) {
 	module, parts := parseQuery(query)
-	split := strings.Split(module, "/")
-	if full, ok := b.cfg.Aliases[split[0]]; ok {
-		split[0] = full
+
+	// Versioned lookups are cached under their own key, as pkg.go.dev
+	// serves every version of a package on its own page.
+	name := b.modulePath(module)
+	_, version := splitVersion(name)
+
+	// Symbols are remembered by the query of their package, so that missing
+	// symbols are not searched for even if the package is no longer cached.
+	key := strings.Join(append([]string{name}, parts...), " ")
+	if err := b.missingSymbols.get(key); err != nil {
+		return symbol{}, err
 	}
 
-	pkg, err := b.searcher.Search(context.Background(), strings.Join(split, "/"))
+	pkg, err := b.searcher.Search(ctx, name)
 	if err != nil {
 		log.Printf("Package request by %s(%q) failed: %v", user.Tag(), query, err)
-		return failEmbed("Error", fmt.Sprintf(searchErr, module)), false
+		return symbol{}, searchError(err, module)
+	}
+	pkg.Name, _ = splitVersion(pkg.URL)
+	pkg.URL = name
+	if version != "" {
+		pkg.Name += "@" + version
 	}
-	pkg.Name = pkg.URL
-	pkg.URL = strings.Join(split, "/")
 
+	sym := symbol{pkg: pkg}
 	switch len(parts) {
 	case 0:
-		return pkgEmbed(pkg, full)
+		return sym, nil
 
 	case 1:
-		if typ, ok := pkg.Types[parts[0]]; ok {
-			return typEmbed(pkg, typ, full)
+		var ok bool
+		if sym.typ, ok = pkg.Types[parts[0]]; ok {
+			sym.kind = kindType
+			return sym, nil
 		}
-		if fn, ok := pkg.Functions[parts[0]]; ok {
-			return fnEmbed(pkg, fn, full)
+		if sym.fn, ok = pkg.Functions[parts[0]]; ok {
+			sym.kind = kindFunc
+			return sym, nil
 		}
-		if cnst, ok := pkg.ConstantMap[parts[0]]; ok {
-			return varEmbed(pkg, cnst, full)
+		if sym.v, ok = pkg.ConstantMap[parts[0]]; ok {
+			sym.kind = kindConst
+			return sym, nil
 		}
-		if vvar, ok := pkg.VariableMap[parts[0]]; ok {
-			return varEmbed(pkg, vvar, full)
+		if sym.v, ok = pkg.VariableMap[parts[0]]; ok {
+			sym.kind = kindVar
+			return sym, nil
 		}
-		return failEmbed("Error: Not Found", fmt.Sprintf(notFound, parts[0], module)), false
+		return symbol{}, b.missingSymbols.add(key, lookupError{"Error: Not Found", fmt.Sprintf(notFound, parts[0], module)})
 
 	default:
 		typ, ok := pkg.Types[parts[0]]
 		if !ok {
-			return failEmbed("Error: Not Found", fmt.Sprintf(notFound, parts[0], module)), false
+			return symbol{}, b.missingSymbols.add(key, lookupError{"Error: Not Found", fmt.Sprintf(notFound, parts[0], module)})
 		}
 
-		method, ok := typ.Methods[parts[1]]
-		if !ok {
-			return failEmbed("Error: Not Found", fmt.Sprintf(notFound, parts[1], module)), false
+		found, external := findMember(pkg, typ, parts[1])
+		switch len(found) {
+		case 0:
+			msg := fmt.Sprintf(notFound, parts[1], module)
+			if len(external) > 0 {
+				msg += fmt.Sprintf(externalEmbeds, strings.Join(external, "`, `"))
+			}
+			return symbol{}, b.missingSymbols.add(key, lookupError{"Error: Not Found", msg})
+		case 1:
+			return found[0], nil
+		default:
+			msg := fmt.Sprintf(ambiguousMember, parts[1], typ.Name, strings.Join(memberPaths(found), "`, `"))
+			return symbol{}, b.missingSymbols.add(key, lookupError{"Error: Ambiguous", msg})
 		}
+	}
+}
 
-		return methodEmbed(pkg, method, full)
+// docs looks up the query and renders its documentation. The symbol is
+// returned too, so that the components of the message are built without
+// looking it up again. It is nil if the query has no symbol, such as when the
+// lookup failed.
+func (b *botState) docs(ctx context.Context, user discord.User, query string) (discord.Embed, *symbol, bool) {
+	if iface, ok := implementersQuery(query); ok {
+		return b.implementersEmbed(ctx, user, iface), nil, false
+	}
+
+	sym, err := b.lookup(ctx, user, query)
+	if err != nil {
+		var lerr lookupError
+		if errors.As(err, &lerr) {
+			return failEmbed(lerr.title, lerr.msg), nil, false
+		}
+		return failEmbed("Error", err.Error()), nil, false
 	}
+
+	embed, more := b.symbolDocs(ctx, sym)
+	return embed, &sym, more
 }
 
-func selectComponent(id string, full bool) *discord.StringSelectComponent {
-	expand := discord.SelectOption{
-		Label:       "Expand",
-		Value:       "expand",
-		Description: "Show more documentation.",
-		Emoji:       &discord.ComponentEmoji{Name: "⬇️"},
+// symbolDocs renders the documentation of a symbol.
+func (b *botState) symbolDocs(ctx context.Context, sym symbol) (discord.Embed, bool) {
+	var embed discord.Embed
+	var more bool
+	switch sym.kind {
+	case kindType:
+		embed, more = typEmbed(sym.pkg, sym.typ, b.implements(ctx, sym))
+	case kindFunc:
+		embed, more = fnEmbed(sym.pkg, sym.fn)
+	case kindConst, kindVar:
+		embed, more = varEmbed(sym.pkg, sym.v)
+	case kindMethod:
+		embed, more = methodEmbed(sym.pkg, sym.method)
+	case kindField:
+		embed, more = fieldEmbed(sym.pkg, sym.typ, sym.field)
+	default:
+		embed, more = pkgEmbed(sym.pkg)
 	}
-	if full {
-		expand = discord.SelectOption{
-			Label:       "Minimize",
-			Value:       "minimize",
-			Description: "Show less documentation.",
-			Emoji:       &discord.ComponentEmoji{Name: "⬆️"},
+
+	if field, ok := sym.deprecatedField(); ok {
+		embed.Fields = append([]discord.EmbedField{field}, embed.Fields...)
+	}
+	if release := b.since(sym); release != "" && release != "go1" {
+		embed.Footer = &discord.EmbedFooter{Text: "Since " + release}
+	}
+	// With several backends, packages show the one that documented them.
+	if sym.kind == kindPackage && b.backends != nil && len(b.backends.backends) > 1 {
+		if backend := b.searcher.backend(sym.pkg.URL); backend != "" {
+			text := "Documented by the " + backend + " backend"
+			if embed.Footer != nil {
+				text = embed.Footer.Text + "\n" + text
+			}
+			embed.Footer = &discord.EmbedFooter{Text: text}
+		}
+	}
+	// Promoted methods and fields are documented on the embedded type.
+	if len(sym.via) > 0 {
+		embed.Fields = append(embed.Fields, discord.EmbedField{
+			Name:  "Promoted",
+			Value: promotedNote(sym.via),
+		})
+	}
+	return embed, more
+}
+
+// content returns the parts of the documentation of the symbol that are split
+// into pages when it is expanded.
+func (s symbol) content() (header, def string, c doc.Comment) {
+	switch s.kind {
+	case kindType:
+		return "", s.typ.Signature, s.typ.Comment
+	case kindFunc:
+		return "", s.fn.Signature, s.fn.Comment
+	case kindConst, kindVar:
+		return "", s.v.Signature, s.v.Comment
+	case kindMethod:
+		return "", s.method.Signature, s.method.Comment
+	case kindField:
+		return "", fieldDef(s.field), fieldComment(s.field)
+	}
+	return pkgHeader(s.pkg), "", s.pkg.Overview
+}
+
+// docsPage renders a page of the expanded documentation of a query, and
+// returns its symbol, as docs does, and the number of pages. Queries that are
+// not split into pages, such as errors, are returned as a single page.
+func (b *botState) docsPage(ctx context.Context, user discord.User, query string, page int) (discord.Embed, *symbol, int) {
+	embed, sym, _ := b.docs(ctx, user, query)
+	if sym == nil {
+		return embed, nil, 1
+	}
+
+	header, def, c := sym.content()
+	pages := docPages(header, def, c, docPageLimit)
+	page = min(max(page, 0), len(pages)-1)
+	embed.Description = pages[page]
+
+	if len(pages) > 1 {
+		text := fmt.Sprintf("Page %d of %d", page+1, len(pages))
+		if embed.Footer != nil {
+			text = embed.Footer.Text + "\n" + text
+		}
+		embed.Footer = &discord.EmbedFooter{Text: text}
+	}
+	return embed, sym, len(pages)
+}
+
+// docsComponent returns the component for a docs message. Actions, such as
+// viewing examples, are added to the menu when they apply to the symbol, which
+// is nil if the message has none. If there is nothing to expand and there are
+// no actions, only a hide button is shown.
+func docsComponent(id string, sym *symbol, full, more bool) discord.InteractiveComponent {
+	var actions []discord.SelectOption
+	if sym != nil {
+		if len(sym.examples()) > 0 {
+			actions = append(actions, examplesOption)
+		}
+		if sym.hasSource() {
+			actions = append(actions, sourceOption)
+		}
+		if _, ok := parentPackage(*sym); sym.kind == kindPackage && ok {
+			actions = append(actions, parentOption)
 		}
 	}
 
+	if !full && !more && len(actions) == 0 {
+		return buttonComponent(id)
+	}
+	return selectComponent(id, full, more, actions...)
+}
+
+// docsComponents returns the rows of components for a docs message: the
+// actions of docsComponent, followed by the browse menus of the symbol.
+func docsComponents(id string, sym *symbol, full, more bool) discord.ContainerComponents {
+	components := discord.ContainerComponents{
+		&discord.ActionRowComponent{docsComponent(id, sym, full, more)},
+	}
+	return append(components, browseComponents(id, sym, "", 0)...)
+}
+
+func selectComponent(id string, full, more bool, actions ...discord.SelectOption) *discord.StringSelectComponent {
 	sel := &discord.StringSelectComponent{
 		CustomID:    discord.ComponentID(id),
 		Placeholder: "Actions",
-		Options: []discord.SelectOption{
-			expand,
-			{
-				Label:       "Hide",
-				Value:       "hide",
-				Description: "Hide the message.",
-				Emoji:       &discord.ComponentEmoji{Name: "❌"},
-			},
-		},
 	}
-	if !full {
+
+	switch {
+	case full:
+		sel.Options = append(sel.Options, discord.SelectOption{
+			Label:       "Minimize",
+			Value:       "minimize",
+			Description: "Show less documentation.",
+			Emoji:       &discord.ComponentEmoji{Name: "⬆️"},
+		})
+	case more:
+		sel.Options = append(sel.Options, discord.SelectOption{
+			Label:       "Expand",
+			Value:       "expand",
+			Description: "Show more documentation.",
+			Emoji:       &discord.ComponentEmoji{Name: "⬇️"},
+		})
+	}
+
+	sel.Options = append(sel.Options, discord.SelectOption{
+		Label:       "Hide",
+		Value:       "hide",
+		Description: "Hide the message.",
+		Emoji:       &discord.ComponentEmoji{Name: "❌"},
+	})
+	sel.Options = append(sel.Options, actions...)
+
+	if !full && more {
 		sel.Options = append(sel.Options, discord.SelectOption{
 			Label:       "Expand (For everyone)",
 			Value:       "expand.all",
@@ -576,26 +1013,100 @@ func buttonComponent(id string) *discord.ButtonComponent {
 	}
 }
 
+// versionRe matches the version suffix of a module in a query, such as
+// @v1.2.3, @v0.0.0-20210101000000-abcdef123456, @go1.18 or @latest.
+//
+// Pre-release identifiers after the first one must be numeric, so that a
+// trailing symbol like "@v1.0.0-rc.1.Client" is not treated as part of the
+// version.
+var versionRe = regexp.MustCompile(`^@(v\d+\.\d+\.\d+(-[\w-]+(\.\d+)*)?|go\d+(\.\d+){0,2}((rc|beta)\d+)?|[\w-]+)`)
+
+// pageButtons returns the buttons to go to the previous and next page. The
+// page number is appended to the prefix for the custom ID of each button.
+func pageButtons(prefix string, page, pages int) *discord.ActionRowComponent {
+	return &discord.ActionRowComponent{
+		&discord.ButtonComponent{
+			Label:    "Prev Page",
+			CustomID: discord.ComponentID(prefix + strconv.Itoa(page-1)),
+			Style:    discord.SecondaryButtonStyle(),
+			Emoji:    &discord.ComponentEmoji{Name: "⬅️"},
+			Disabled: page == 0,
+		},
+		&discord.ButtonComponent{
+			Label:    "Next Page",
+			CustomID: discord.ComponentID(prefix + strconv.Itoa(page+1)),
+			Style:    discord.SecondaryButtonStyle(),
+			Emoji:    &discord.ComponentEmoji{Name: "➡️"},
+			Disabled: page == pages-1,
+		},
+	}
+}
+
 func parseQuery(query string) (string, []string) {
-	var split []string
-	var first string
+	module, parts := splitQuery(query)
+	for i := range parts {
+		parts[i] = strings.ToLower(parts[i])
+	}
+	return module, parts
+}
 
-	if strings.Contains(query, "@") {
-		split = strings.Split(query, " ")
-		first = split[0]
-	} else {
-		query = strings.ReplaceAll(query, " ", ".")
-		dir, base := path.Split(strings.ToLower(query))
-		split = strings.Split(base, ".")
-		first = dir + split[0]
+// splitQuery splits a docs query into its module and the parts of its symbol
+// like parseQuery, but keeps the case of the parts, so that they can be
+// matched case sensitively.
+func splitQuery(query string) (string, []string) {
+	// Versions are removed from the query before it is split, and added back
+	// onto the module afterwards.
+	var version string
+	if i := strings.Index(query, "@"); i != -1 {
+		if loc := versionRe.FindStringSubmatchIndex(query[i:]); loc != nil {
+			version = query[i+loc[2] : i+loc[3]]
+			query = query[:i] + query[i+loc[1]:]
+		}
 	}
 
-	if strings.HasPrefix(first, "x/") {
-		first = "golang.org/" + first
+	query = strings.ReplaceAll(strings.TrimSpace(query), " ", ".")
+	dir, base := path.Split(query)
+	split := strings.Split(base, ".")
+
+	// Only the standard library and its aliases are matched case
+	// insensitively. Other module paths are case sensitive, as the module
+	// proxies encode their case.
+	first := dir + split[0]
+	key := strings.ToLower(first)
+	switch {
+	case strings.HasPrefix(key, "x/"):
+		first = "golang.org/" + key
+	case stdlibAliases[key] != "":
+		first = stdlibAliases[key]
+	case stdlib[key]:
+		first = key
 	}
-	if complete, ok := stdlibAliases[first]; ok {
-		first = complete
+	if version != "" {
+		first += "@" + version
 	}
 
 	return first, split[1:]
 }
+
+// modulePath applies the configured aliases to the first element of the
+// module path. The version of the module is kept.
+func (b *botState) modulePath(module string) string {
+	name, version := splitVersion(module)
+	split := strings.Split(name, "/")
+	if full, ok := b.cfg.Aliases[strings.ToLower(split[0])]; ok {
+		split[0] = full
+	}
+
+	name = strings.Join(split, "/")
+	if version != "" {
+		name += "@" + version
+	}
+	return name
+}
+
+// splitVersion splits a module into its path and version. The version is
+// empty if the module is not pinned to one.
+func splitVersion(module string) (string, string) {
+	name, version, _ := strings.Cut(module, "@")
+	return name, version
+}
@@ -1,6 +1,12 @@
 package main
 
-import "testing"
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hhhapz/doc"
+)
 
 func TestParseQuery(t *testing.T) {
 	cases := []struct {
@@ -69,6 +75,54 @@ func TestParseQuery(t *testing.T) {
 			module: "github.com/bwmarrin/discordgo",
 			parts:  []string{"session", "addhandler"},
 		},
+		{
+			name:   "stdlib version",
+			query:  "fmt@go1.18",
+			module: "fmt@go1.18",
+			parts:  nil,
+		},
+		{
+			name:   "stdlib redirect version type",
+			query:  "json@go1.18.Marshal",
+			module: "encoding/json@go1.18",
+			parts:  []string{"marshal"},
+		},
+		{
+			name:   "custom version with space",
+			query:  "github.com/bwmarrin/discordgo@v0.23.2 Session AddHandler",
+			module: "github.com/bwmarrin/discordgo@v0.23.2",
+			parts:  []string{"session", "addhandler"},
+		},
+		{
+			name:   "custom pre-release version",
+			query:  "github.com/hhhapz/doc@v1.0.0-rc.1.Package",
+			module: "github.com/hhhapz/doc@v1.0.0-rc.1",
+			parts:  []string{"package"},
+		},
+		{
+			name:   "custom pseudo-version",
+			query:  "golang.org/x/sync@v0.0.0-20210220032951-036812b2e83c errgroup",
+			module: "golang.org/x/sync@v0.0.0-20210220032951-036812b2e83c",
+			parts:  []string{"errgroup"},
+		},
+		{
+			name:   "custom version from url",
+			query:  "github.com/hhhapz/doc@v1.2.1.Searcher.Search",
+			module: "github.com/hhhapz/doc@v1.2.1",
+			parts:  []string{"searcher", "search"},
+		},
+		{
+			name:   "mixed case module with version",
+			query:  "github.com/BurntSushi/toml@v1.3.2 Decoder.Decode",
+			module: "github.com/BurntSushi/toml@v1.3.2",
+			parts:  []string{"decoder", "decode"},
+		},
+		{
+			name:   "mixed case stdlib",
+			query:  "Net/HTTP.Client",
+			module: "net/http",
+			parts:  []string{"client"},
+		},
 	}
 
 	for _, c := range cases {
@@ -88,3 +142,15 @@ func TestParseQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchError(t *testing.T) {
+	err := searchError(fmt.Errorf("pkgsite: %w", context.DeadlineExceeded), "net/http")
+	if want := (lookupError{"Error: Timed Out", fmt.Sprintf(timeoutErr, "net/http")}); err != want {
+		t.Errorf("searchError(timeout) = %v, want %v", err, want)
+	}
+
+	err = searchError(doc.InvalidStatusError(404), "net/htp")
+	if want := (lookupError{"Error", fmt.Sprintf(searchErr, "net/htp")}); err != want {
+		t.Errorf("searchError(404) = %v, want %v", err, want)
+	}
+}
@@ -9,38 +9,47 @@ import (
 )
 
 const (
-	docLimit      = 1000
 	shortDocLimit = 600
-	defLimit      = 1000
+	docPageLimit  = 3000
 
 	accentColor = 0x00ADD8
 )
 
-func pkgEmbed(pkg doc.Package, full bool) (discord.Embed, bool) {
-	c, more := comment(pkg.Overview, 32, full)
+func pkgEmbed(pkg doc.Package) (discord.Embed, bool) {
+	c, more := comment(pkg.Overview, 32)
 	return discord.Embed{
-		Title: "Package " + pkg.Name,
-		URL:   "https://pkg.go.dev/" + pkg.URL,
-		Description: fmt.Sprintf("**Types:** %d\n**Functions:** %d\n\n%s",
-			len(pkg.Types), len(pkg.Functions), c),
-		Color: accentColor,
+		Title:       "Package " + pkg.Name,
+		URL:         "https://pkg.go.dev/" + pkg.URL,
+		Description: fmt.Sprintf("%s\n\n%s", pkgHeader(pkg), c),
+		Color:       accentColor,
 	}, more
 }
 
-func typEmbed(pkg doc.Package, typ doc.Type, full bool) (discord.Embed, bool) {
-	def, dMore := typdef(typ.Signature, full)
-	c, cMore := comment(typ.Comment, len(def), full)
-	return discord.Embed{
+func pkgHeader(pkg doc.Package) string {
+	return fmt.Sprintf("**Types:** %d\n**Functions:** %d", len(pkg.Types), len(pkg.Functions))
+}
+
+func typEmbed(pkg doc.Package, typ doc.Type, implements []string) (discord.Embed, bool) {
+	def, dMore := typdef(typ.Signature)
+	c, cMore := comment(typ.Comment, len(def))
+	embed := discord.Embed{
 		Title:       fmt.Sprintf("%s: %s", pkg.Name, typ.Name),
 		URL:         fmt.Sprintf("https://pkg.go.dev/%s#%s", pkg.URL, typ.Name),
 		Description: fmt.Sprintf("```go\n%s```\n%s", def, c),
 		Color:       accentColor,
-	}, dMore || cMore
+	}
+	if len(implements) > 0 {
+		embed.Fields = []discord.EmbedField{{
+			Name:  "Implements",
+			Value: strings.Join(implements, ", "),
+		}}
+	}
+	return embed, dMore || cMore
 }
 
-func fnEmbed(pkg doc.Package, fn doc.Function, full bool) (discord.Embed, bool) {
-	def, dMore := typdef(fn.Signature, full)
-	c, cMore := comment(fn.Comment, len(def), full)
+func fnEmbed(pkg doc.Package, fn doc.Function) (discord.Embed, bool) {
+	def, dMore := typdef(fn.Signature)
+	c, cMore := comment(fn.Comment, len(def))
 	return discord.Embed{
 		Title:       fmt.Sprintf("%s: %s", pkg.Name, fn.Name),
 		URL:         fmt.Sprintf("https://pkg.go.dev/%s#%s", pkg.URL, fn.Name),
@@ -49,9 +58,9 @@ func fnEmbed(pkg doc.Package, fn doc.Function, full bool) (discord.Embed, bool)
 	}, dMore || cMore
 }
 
-func varEmbed(pkg doc.Package, v doc.Variable, full bool) (discord.Embed, bool) {
-	def, dMore := typdef(v.Signature, full)
-	c, cMore := comment(v.Comment, len(def), full)
+func varEmbed(pkg doc.Package, v doc.Variable) (discord.Embed, bool) {
+	def, dMore := typdef(v.Signature)
+	c, cMore := comment(v.Comment, len(def))
 	return discord.Embed{
 		Title:       fmt.Sprintf("%s: %s", pkg.Name, v.Name),
 		URL:         fmt.Sprintf("https://pkg.go.dev/%s#%s", pkg.URL, v.Name),
@@ -60,9 +69,9 @@ func varEmbed(pkg doc.Package, v doc.Variable, full bool) (discord.Embed, bool)
 	}, dMore || cMore
 }
 
-func methodEmbed(pkg doc.Package, method doc.Method, full bool) (discord.Embed, bool) {
-	def, dMore := typdef(method.Signature, full)
-	c, cMore := comment(method.Comment, len(def), full)
+func methodEmbed(pkg doc.Package, method doc.Method) (discord.Embed, bool) {
+	def, dMore := typdef(method.Signature)
+	c, cMore := comment(method.Comment, len(def))
 	return discord.Embed{
 		Title:       fmt.Sprintf("%s: %s.%s", pkg.Name, method.For, method.Name),
 		URL:         fmt.Sprintf("https://pkg.go.dev/%s#%s.%s", pkg.URL, method.For, method.Name),
@@ -93,8 +102,30 @@ Here are some example queries:` + "```md" + `
 # Search a type method
 /docs query:github.com/hhhapz/doc searcher search
 
+# Search a struct field, or a promoted field or method
+/docs query:net/http request header
+
+# Search a symbol in all packages
+/docs item:NewReader
+
 # Many standard library types have aliases
 /docs query:http (-> net/http)
+
+# Warn about symbols that are newer than your Go version
+/docs module:slices item:Contains version:go1.20
+
+# Search a specific version
+/docs query:fmt@go1.18 println
+/docs query:github.com/hhhapz/doc@v1.2.1 package
+
+# List the types that implement an interface
+/docs query:io reader implementers
+
+# Search the doc comments of all packages
+/docs module:search text:graceful shutdown stdlib:true
+
+# Compare the API of two versions
+/docs module:diff item:github.com/hhhapz/doc from:v1.0.0 to:v1.2.1
 ` + "```",
 		Footer: &discord.EmbedFooter{Text: "Source Code: https://github.com/DiscordGophers/dr-docso"},
 		Color:  accentColor,
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/hhhapz/doc"
+)
+
+// examplePageLimit is the maximum length of the code and output shown on
+// a single page of an example.
+const examplePageLimit = 3500
+
+var examplesOption = discord.SelectOption{
+	Label:       "Examples",
+	Value:       "examples",
+	Description: "Show usage examples.",
+	Emoji:       &discord.ComponentEmoji{Name: "📝"},
+}
+
+// handleExamples lists the examples of the query privately. If there is only
+// one example, it is displayed right away.
+func (b *botState) handleExamples(e *gateway.InteractionCreateEvent, data *interactionData) {
+	ctx, cancel := context.WithTimeout(context.Background(), componentTimeout)
+	defer cancel()
+
+	sym, err := b.lookup(ctx, *e.User, data.query)
+	if err != nil {
+		b.respondError(e, err.Error())
+		return
+	}
+
+	examples := sym.examples()
+	if len(examples) == 1 {
+		b.respondExample(e, api.MessageInteractionWithSource, data.id, sym, 0, 0)
+		return
+	}
+
+	var list []string
+	for _, ex := range examples {
+		list = append(list, "- "+exampleTitle(ex))
+	}
+
+	b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+		Type: api.MessageInteractionWithSource,
+		Data: &api.InteractionResponseData{
+			Flags: discord.EphemeralMessage,
+			Embeds: &[]discord.Embed{{
+				Title:       fmt.Sprintf("%s: %d Examples", sym.title(), len(examples)),
+				Description: strings.Join(list, "\n"),
+				Color:       accentColor,
+			}},
+			Components: &discord.ContainerComponents{
+				&discord.ActionRowComponent{examplesSelect(data.id, examples, -1, 0)},
+			},
+		},
+	})
+}
+
+// handleExampleComponent handles the example select menu and page buttons.
+// The command is either "<id>", with the example index or a page of the menu
+// as the selected value, or "<id>.<index>.<page>".
+func (b *botState) handleExampleComponent(e *gateway.InteractionCreateEvent, component discord.ComponentInteraction, cmd string) {
+	split := strings.Split(cmd, ".")
+
+	mu.Lock()
+	data, ok := interactionMap[split[0]]
+	mu.Unlock()
+	if !ok {
+		b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+			Type: api.UpdateMessage,
+			Data: &api.InteractionResponseData{
+				Embeds:     &[]discord.Embed{failEmbed("Error", expired)},
+				Components: &discord.ContainerComponents{},
+			},
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), componentTimeout)
+	defer cancel()
+	sym, err := b.lookup(ctx, *e.User, data.query)
+	if err != nil {
+		b.respondError(e, err.Error())
+		return
+	}
+
+	var idx, page int
+	switch len(split) {
+	case 1:
+		sel, ok := component.(*discord.StringSelectInteraction)
+		if !ok || len(sel.Values) == 0 {
+			return
+		}
+		// Only the menu changes, as with the browse menus.
+		if pageStr, ok := strings.CutPrefix(sel.Values[0], browsePage); ok && len(e.Message.Components) > 0 {
+			page, _ := strconv.Atoi(pageStr)
+			components := e.Message.Components
+			components[0] = &discord.ActionRowComponent{examplesSelect(data.id, sym.examples(), -1, page)}
+			b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+				Type: api.UpdateMessage,
+				Data: &api.InteractionResponseData{Components: &components},
+			})
+			return
+		}
+		idx, _ = strconv.Atoi(sel.Values[0])
+	case 3:
+		idx, _ = strconv.Atoi(split[1])
+		page, _ = strconv.Atoi(split[2])
+	default:
+		return
+	}
+
+	log.Printf("%s used docs example(%q, %d, %d)", e.User.Tag(), data.query, idx, page)
+	b.respondExample(e, api.UpdateMessage, data.id, sym, idx, page)
+}
+
+// respondExample displays a single page of an example, with a menu to choose
+// other examples, and buttons to change the page.
+func (b *botState) respondExample(e *gateway.InteractionCreateEvent, typ api.InteractionResponseType, id string, sym symbol, idx, page int) {
+	examples := sym.examples()
+	if idx < 0 || idx >= len(examples) {
+		return
+	}
+
+	ex := examples[idx]
+	pages := examplePages(ex)
+	if page < 0 || page >= len(pages) {
+		page = 0
+	}
+
+	comps := discord.ContainerComponents{
+		&discord.ActionRowComponent{examplesSelect(id, examples, idx, idx/browseLimit)},
+	}
+	if len(pages) > 1 {
+		prefix := fmt.Sprintf("docs.example.%s.%d.", id, idx)
+		comps = append(comps, pageButtons(prefix, page, len(pages)))
+	}
+
+	b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+		Type: typ,
+		Data: &api.InteractionResponseData{
+			Flags: discord.EphemeralMessage,
+			Embeds: &[]discord.Embed{{
+				Title:       fmt.Sprintf("%s: Example %s", sym.pkg.Name, exampleTitle(ex)),
+				URL:         fmt.Sprintf("https://pkg.go.dev/%s#example-%s", sym.pkg.URL, ex.Name),
+				Description: pages[page],
+				Footer: &discord.EmbedFooter{
+					Text: fmt.Sprintf("Example %d of %d\nPage %d of %d", idx+1, len(examples), page+1, len(pages)),
+				},
+				Color: accentColor,
+			}},
+			Components: &comps,
+		},
+	})
+}
+
+// examplesSelect returns the menu to choose an example, at the page. Packages
+// with more than 25 examples have them split into pages, as browseSelect does.
+func examplesSelect(id string, examples []doc.Example, selected, page int) *discord.StringSelectComponent {
+	var opts []discord.SelectOption
+	for i, ex := range examples {
+		opts = append(opts, discord.SelectOption{
+			Label:   truncate(exampleTitle(ex), 100),
+			Value:   strconv.Itoa(i),
+			Default: i == selected,
+		})
+	}
+	return browseSelect("docs.example."+id, "Display Example", opts, page)
+}
+
+// exampleTitle formats the name of an example for display, turning
+// "FileServer-StripPrefix" into "FileServer (StripPrefix)".
+func exampleTitle(ex doc.Example) string {
+	name, suffix, ok := strings.Cut(ex.Name, "-")
+	if !ok {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, suffix)
+}
+
+// examplePages splits the code of an example into pages on line boundaries,
+// so that code blocks are never cut. The output is shown on the last page.
+func examplePages(ex doc.Example) []string {
+	output := ""
+	if ex.Output != "" {
+		output = fmt.Sprintf("\n**Output:**```\n%s```", ex.Output)
+		if len(output) > examplePageLimit {
+			output = truncate(output, examplePageLimit-len("```")) + "```"
+		}
+	}
+
+	pages := codePages(ex.Code, examplePageLimit)
+
+	last := len(pages) - 1
+	if len(pages[last])+len(output) > examplePageLimit+len("```go\n```") {
+		pages = append(pages, output)
+	} else {
+		pages[last] += output
+	}
+	return pages
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/hhhapz/doc"
+)
+
+// maxEmbedDepth is how deep embedded fields are searched for promoted fields
+// and methods.
+const maxEmbedDepth = 4
+
+// structField is a field of a struct type, parsed from the signature of the
+// type.
+type structField struct {
+	name    string
+	typ     string
+	tag     string
+	doc     string
+	comment string
+	// embedded is the name of the embedded type, without pointer or package
+	// qualifier, if the field is embedded.
+	embedded string
+	// local is true if the embedded type is declared in the same package.
+	local bool
+}
+
+// structFields parses the fields of a struct type signature, such as
+// "type T struct { ... }". Nil is returned if the type is not a struct.
+func structFields(signature string) []structField {
+	const header = "package p\n"
+	src := header + signature
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	var st *ast.StructType
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE || len(gen.Specs) == 0 {
+			continue
+		}
+		if st, ok = gen.Specs[0].(*ast.TypeSpec).Type.(*ast.StructType); ok {
+			break
+		}
+	}
+	if st == nil {
+		return nil
+	}
+
+	text := func(node ast.Node) string {
+		return src[fset.Position(node.Pos()).Offset:fset.Position(node.End()).Offset]
+	}
+
+	var fields []structField
+	for _, field := range st.Fields.List {
+		sf := structField{typ: text(field.Type)}
+		if field.Tag != nil {
+			sf.tag = field.Tag.Value
+		}
+		if field.Doc != nil {
+			sf.doc = strings.TrimSpace(field.Doc.Text())
+		}
+		if field.Comment != nil {
+			sf.comment = strings.TrimSpace(field.Comment.Text())
+		}
+
+		if len(field.Names) == 0 {
+			sf.embedded, sf.local = embeddedName(field.Type)
+			sf.name = sf.embedded
+			fields = append(fields, sf)
+			continue
+		}
+		for _, name := range field.Names {
+			sf.name = name.Name
+			fields = append(fields, sf)
+		}
+	}
+	return fields
+}
+
+// embeddedName returns the type name of an embedded field, such as T for *T
+// or pkg.T. local is false if the type is qualified with a package.
+func embeddedName(expr ast.Expr) (name string, local bool) {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.SelectorExpr:
+			return e.Sel.Name, false
+		case *ast.Ident:
+			return e.Name, true
+		default:
+			return "", false
+		}
+	}
+}
+
+// findMember finds the method or field of a type, which may be promoted from
+// an embedded field. Like selectors in Go, members are found at the shallowest
+// depth, and if there is more than one at that depth, the selector is
+// ambiguous, and all of them are returned.
+//
+// Only types embedded from the same package are searched, as the package of
+// other embedded types is not known from the signature. If the member is not
+// found, those types are returned as external, such as "sync.Mutex", so that
+// the user can be told that members promoted from them are not resolved.
+//
+// If the member is promoted, the via path starts with the name of typ, followed
+// by the embedded fields that the member is promoted through.
+func findMember(pkg doc.Package, typ doc.Type, name string) (found []symbol, external []string) {
+	type level struct {
+		typ doc.Type
+		via []string
+	}
+
+	queue := []level{{typ: typ}}
+	// Types seen at a shallower depth are not searched again, as they would
+	// have been found there. The same type embedded twice at one depth is,
+	// so that its members are ambiguous.
+	seen := map[string]bool{}
+	for depth := 0; depth <= maxEmbedDepth && len(queue) > 0; depth++ {
+		for _, l := range queue {
+			seen[l.typ.Name] = true
+		}
+
+		var next []level
+		for _, l := range queue {
+			if method, ok := l.typ.Methods[name]; ok {
+				found = append(found, symbol{pkg: pkg, kind: kindMethod, typ: l.typ, method: method, via: l.via})
+			}
+
+			for _, field := range structFields(l.typ.Signature) {
+				if strings.EqualFold(field.name, name) {
+					found = append(found, symbol{pkg: pkg, kind: kindField, typ: l.typ, field: field, via: l.via})
+				}
+				if field.embedded == "" {
+					continue
+				}
+				if !field.local {
+					if ext := strings.TrimPrefix(field.typ, "*"); !slices.Contains(external, ext) {
+						external = append(external, ext)
+					}
+					continue
+				}
+				if embedded, ok := pkg.Types[strings.ToLower(field.embedded)]; ok && !seen[embedded.Name] {
+					via := append([]string{}, l.via...)
+					if len(via) == 0 {
+						via = append(via, l.typ.Name)
+					}
+					next = append(next, level{typ: embedded, via: append(via, field.embedded)})
+				}
+			}
+		}
+		if len(found) > 0 {
+			return found, nil
+		}
+		queue = next
+	}
+	return nil, external
+}
+
+// memberPaths formats the paths that the members found by findMember are
+// promoted through, such as "ReadWriter.Reader.Size".
+func memberPaths(found []symbol) []string {
+	var paths []string
+	for _, sym := range found {
+		name := sym.field.name
+		if sym.kind == kindMethod {
+			name = sym.method.Name
+		}
+		via := sym.via
+		if len(via) == 0 {
+			via = []string{sym.typ.Name}
+		}
+		paths = append(paths, strings.Join(append(via[:len(via):len(via)], name), "."))
+	}
+	return paths
+}
+
+// promotedNote describes the embedded fields that a member is promoted
+// through, as returned by findMember.
+func promotedNote(via []string) string {
+	return fmt.Sprintf("Promoted from embedded field `%s` of `%s`.", strings.Join(via[1:], "."), via[0])
+}
+
+// fieldDef returns the declaration of the field, as shown in its embed.
+func fieldDef(field structField) string {
+	def := field.name + " " + field.typ
+	if field.embedded != "" {
+		def = field.typ
+	}
+	if field.tag != "" {
+		def += " " + field.tag
+	}
+	return def
+}
+
+// fieldComment returns the doc comment and the line comment of the field.
+func fieldComment(field structField) doc.Comment {
+	var c doc.Comment
+	for _, text := range []string{field.doc, field.comment} {
+		if text != "" {
+			c = append(c, doc.Paragraph(text))
+		}
+	}
+	return c
+}
+
+func fieldEmbed(pkg doc.Package, typ doc.Type, field structField) (discord.Embed, bool) {
+	def := fieldDef(field)
+	c, more := comment(fieldComment(field), len(def))
+	return discord.Embed{
+		Title:       fmt.Sprintf("%s: %s.%s", pkg.Name, typ.Name, field.name),
+		URL:         fmt.Sprintf("https://pkg.go.dev/%s#%s.%s", pkg.URL, typ.Name, field.name),
+		Description: fmt.Sprintf("```go\n%s\n```\n%s", def, c),
+		Color:       accentColor,
+	}, more
+}
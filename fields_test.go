@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hhhapz/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructFields(t *testing.T) {
+	fields := structFields("type Config struct {\n" +
+		"\t// MinVersion contains the minimum TLS version.\n" +
+		"\tMinVersion uint16 `json:\"min\"` // e.g. VersionTLS12\n" +
+		"\tA, B int\n" +
+		"\t*Reader\n" +
+		"\tsync.Mutex\n" +
+		"\t// contains filtered or unexported fields\n" +
+		"}")
+
+	assert.Equal(t, []structField{
+		{name: "MinVersion", typ: "uint16", tag: "`json:\"min\"`", doc: "MinVersion contains the minimum TLS version.", comment: "e.g. VersionTLS12"},
+		{name: "A", typ: "int"},
+		{name: "B", typ: "int"},
+		{name: "Reader", typ: "*Reader", embedded: "Reader", local: true},
+		{name: "Mutex", typ: "sync.Mutex", embedded: "Mutex"},
+	}, fields)
+
+	assert.Nil(t, structFields("type Reader interface {\n\tRead(p []byte) (n int, err error)\n}"))
+}
+
+func TestFindMember(t *testing.T) {
+	pkg := doc.Package{
+		Types: map[string]doc.Type{
+			"readwriter": {
+				Name:      "ReadWriter",
+				Signature: "type ReadWriter struct {\n\t*Reader\n\t*Writer\n}",
+			},
+			"reader": {
+				Name:      "Reader",
+				Signature: "type Reader struct {\n\tSize int\n}",
+				Methods: map[string]doc.Method{
+					"read": {For: "Reader", Function: doc.Function{Name: "Read"}},
+				},
+			},
+			"writer": {
+				Name:      "Writer",
+				Signature: "type Writer struct {\n\tSize int\n}",
+			},
+			"locked": {
+				Name:      "Locked",
+				Signature: "type Locked struct {\n\t*sync.Mutex\n\tReader\n}",
+			},
+		},
+	}
+	rw := pkg.Types["readwriter"]
+
+	found, _ := findMember(pkg, rw, "read")
+	if assert.Len(t, found, 1) {
+		assert.Equal(t, kindMethod, found[0].kind)
+		assert.Equal(t, []string{"ReadWriter", "Reader"}, found[0].via)
+		assert.Equal(t, "Promoted from embedded field `Reader` of `ReadWriter`.", promotedNote(found[0].via))
+	}
+
+	found, _ = findMember(pkg, rw, "writer")
+	if assert.Len(t, found, 1) {
+		assert.Equal(t, kindField, found[0].kind)
+		assert.Empty(t, found[0].via)
+	}
+
+	found, _ = findMember(pkg, rw, "size")
+	assert.Equal(t, []string{"ReadWriter.Reader.Size", "ReadWriter.Writer.Size"}, memberPaths(found))
+
+	found, external := findMember(pkg, rw, "missing")
+	assert.Empty(t, found)
+	assert.Empty(t, external)
+
+	found, external = findMember(pkg, pkg.Types["locked"], "lock")
+	assert.Empty(t, found)
+	assert.Equal(t, []string{"sync.Mutex"}, external)
+}
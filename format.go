@@ -6,7 +6,7 @@ import (
 	"github.com/hhhapz/doc"
 )
 
-func typdef(def string, full bool) (string, bool) {
+func typdef(def string) (string, bool) {
 	split := strings.Split(def, "\n")
 
 	// Show upto 8 lines, good for single-line interfaces and the sort.
@@ -15,72 +15,31 @@ func typdef(def string, full bool) (string, bool) {
 	}
 
 	// More than one line, but there is more declaration
-	if !full {
-		return split[0], true
-	}
-
-	b := strings.Builder{}
-	b.Grow(len(def))
-
-	var more bool
-
-	for _, line := range strings.Split(def, "\n") {
-		b.WriteRune('\n')
-
-		if len(line)+b.Len() > defLimit {
-			b.WriteString("// full signature omitted")
-			more = true
-			break
-		}
-		b.WriteString(line)
-	}
-	return b.String(), more
+	return split[0], true
 }
 
-func comment(c doc.Comment, initial int, full bool) (string, bool) {
+func comment(c doc.Comment, initial int) (string, bool) {
 	if len(c) == 0 {
 		return "*No documentation found*", false
 	}
 
-	limit := docLimit
-	if !full {
-		limit = shortDocLimit
-	}
-
-	// if !full {
-	// 	if md := c.Markdown(); len(md) < 500 {
-	// 		return md, false
-	// 	}
-
-	// 	md, more := c[0].Markdown(), len(c) > 1
-	// 	if len(md) > 600 {
-	// 		md = md[:500]
-	// 		more = true
-	// 	}
-	// 	if more {
-	// 		md += "...\n\n*More documentation omitted*"
-	// 	}
-
-	// 	return md, more
-	// }
-
 	var parts doc.Comment
 	var more bool
 
 	length := initial
 	for i, note := range c {
-		if _, ok := note.(doc.Pre); !full && ok {
+		if _, ok := note.(doc.Pre); ok {
 			parts = append(parts, doc.Paragraph("*More documentation omitted...*"))
 			more = true
 			break
 		}
-		if i > 3 && !full {
+		if i > 3 {
 			parts = append(parts, doc.Paragraph("*More documentation omitted...*"))
 			more = true
 			break
 		}
 		l := len(note.Text())
-		if l+length > limit {
+		if l+length > shortDocLimit {
 			parts = append(parts, doc.Paragraph("*More documentation omitted...*"))
 			more = true
 			break
@@ -90,3 +49,85 @@ func comment(c doc.Comment, initial int, full bool) (string, bool) {
 	}
 	return parts.Markdown(), more
 }
+
+// docPages splits the full documentation of an item into pages of up to limit
+// bytes. The header, the signature and each block of the comment are kept on
+// a single page where possible. Signatures and code blocks that are too long
+// are split on line boundaries, so that a code block is never cut in the
+// middle.
+func docPages(header, def string, c doc.Comment, limit int) []string {
+	var blocks []string
+	if header != "" {
+		blocks = append(blocks, header)
+	}
+	if def != "" {
+		blocks = append(blocks, codePages(def, limit)...)
+	}
+	if len(c) == 0 {
+		blocks = append(blocks, "*No documentation found*")
+	}
+	for _, note := range c {
+		md := note.Markdown()
+		if len(md) <= limit {
+			blocks = append(blocks, md)
+			continue
+		}
+		if pre, ok := note.(doc.Pre); ok {
+			blocks = append(blocks, codePages(string(pre), limit)...)
+			continue
+		}
+		blocks = append(blocks, textPages(md, limit)...)
+	}
+
+	var pages []string
+	var cur strings.Builder
+	for _, block := range blocks {
+		if cur.Len() > 0 && cur.Len()+len(block)+2 > limit {
+			pages = append(pages, cur.String())
+			cur.Reset()
+		}
+		if cur.Len() > 0 {
+			cur.WriteString("\n\n")
+		}
+		cur.WriteString(block)
+	}
+	return append(pages, cur.String())
+}
+
+// textPages splits text into parts of up to limit bytes on word boundaries.
+func textPages(text string, limit int) []string {
+	var pages []string
+	for len(text) > limit {
+		cut := strings.LastIndexByte(text[:limit], ' ')
+		if cut <= 0 {
+			cut = limit
+		}
+		pages = append(pages, text[:cut])
+		text = strings.TrimLeft(text[cut:], " ")
+	}
+	return append(pages, text)
+}
+
+// codePages splits code into go code blocks of up to limit bytes. The code is
+// split on line boundaries, so that a line is never cut between two pages.
+func codePages(code string, limit int) []string {
+	var pages []string
+	var cur strings.Builder
+	flush := func() {
+		pages = append(pages, "```go\n"+cur.String()+"```")
+		cur.Reset()
+	}
+
+	for _, line := range strings.Split(code, "\n") {
+		if len(line) > limit {
+			line = line[:limit]
+		}
+		if cur.Len()+len(line)+1 > limit {
+			flush()
+		}
+		cur.WriteString(line)
+		cur.WriteByte('\n')
+	}
+	flush()
+	return pages
+}
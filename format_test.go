@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hhhapz/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocPages(t *testing.T) {
+	c := doc.Comment{
+		doc.Paragraph(strings.Repeat("word ", 30)),
+		doc.Pre("a := 1\nb := 2\n"),
+		doc.Heading("Usage"),
+		doc.Paragraph(strings.Repeat("more ", 30)),
+	}
+
+	pages := docPages("", "func F()", c, 200)
+	assert.Len(t, pages, 2)
+	assert.True(t, strings.HasPrefix(pages[0], "```go\nfunc F()\n```"))
+	for _, page := range pages {
+		assert.LessOrEqual(t, len(page), 200)
+		assert.Zero(t, strings.Count(page, "```")%2, "code block cut in page %q", page)
+	}
+	assert.True(t, strings.HasSuffix(pages[1], c[3].Markdown()))
+
+	pages = docPages("**Types:** 1", "", nil, 200)
+	assert.Equal(t, []string{"**Types:** 1\n\n*No documentation found*"}, pages)
+}
+
+func TestTextPages(t *testing.T) {
+	pages := textPages("aaa bbb ccc ddd", 8)
+	assert.Equal(t, []string{"aaa bbb", "ccc ddd"}, pages)
+
+	pages = textPages("aaaaaaaaaa", 4)
+	assert.Equal(t, []string{"aaaa", "aaaa", "aa"}, pages)
+}
@@ -11,6 +11,7 @@ require (
 	github.com/hhhapz/doc v1.2.1
 	github.com/lithammer/fuzzysearch v1.1.8
 	github.com/stretchr/testify v1.7.0
+	golang.org/x/mod v0.17.0
 	golang.org/x/net v0.25.0
 )
 
@@ -0,0 +1,168 @@
+// Package goapi reads the API files of a Go installation, found in
+// $GOROOT/api, to find the Go release that added a standard library package
+// or symbol.
+package goapi
+
+import (
+	"bufio"
+	"go/version"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Versions maps standard library packages and symbols to the release that
+// added them.
+type Versions struct {
+	packages map[string]string
+	symbols  map[string]map[string]string
+	releases map[string]bool
+}
+
+// New returns an empty set of versions.
+func New() *Versions {
+	return &Versions{
+		packages: map[string]string{},
+		symbols:  map[string]map[string]string{},
+		releases: map[string]bool{},
+	}
+}
+
+// Load reads the go1*.txt files of an api directory, such as $GOROOT/api.
+func Load(dir string) (*Versions, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "go1*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, os.ErrNotExist
+	}
+
+	v := New()
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		err = v.Parse(f, strings.TrimSuffix(filepath.Base(file), ".txt"))
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// Parse reads an API file of a release, such as go1.21.txt. If a symbol is
+// listed in several files, the earliest release is kept.
+func (v *Versions) Parse(r io.Reader, release string) error {
+	v.releases[release] = true
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		pkg, name, ok := parseLine(sc.Text())
+		if !ok {
+			continue
+		}
+
+		v.packages[pkg] = earliest(v.packages[pkg], release)
+		if name == "" {
+			continue
+		}
+		if v.symbols[pkg] == nil {
+			v.symbols[pkg] = map[string]string{}
+		}
+		v.symbols[pkg][name] = earliest(v.symbols[pkg][name], release)
+	}
+	return sc.Err()
+}
+
+// Releases returns the releases that API files were parsed for, newest first.
+func (v *Versions) Releases() []string {
+	releases := make([]string, 0, len(v.releases))
+	for release := range v.releases {
+		releases = append(releases, release)
+	}
+	slices.SortFunc(releases, func(a, b string) int {
+		return version.Compare(b, a)
+	})
+	return releases
+}
+
+// Package returns the release that added the package, or an empty string if
+// it is not known.
+func (v *Versions) Package(pkg string) string {
+	return v.packages[pkg]
+}
+
+// Symbol returns the release that added the symbol of the package, or an
+// empty string if it is not known. Methods and struct fields are named
+// "<type>.<name>".
+func (v *Versions) Symbol(pkg, name string) string {
+	return v.symbols[pkg][name]
+}
+
+// parseLine parses a line of an API file, such as
+//
+//	pkg bytes, method (*Buffer) Available() int #53685
+//
+// and returns the package and the name of the symbol, "Buffer.Available".
+// Embedded fields are not symbols, so only their package is returned.
+func parseLine(line string) (pkg, name string, ok bool) {
+	rest, ok := strings.CutPrefix(line, "pkg ")
+	if !ok {
+		return "", "", false
+	}
+	pkg, decl, ok := strings.Cut(rest, ", ")
+	if !ok {
+		return "", "", false
+	}
+	// Platform specific symbols are suffixed with the platform, such as
+	// "syscall (linux-386)".
+	pkg, _, _ = strings.Cut(pkg, " ")
+
+	kind, decl, _ := strings.Cut(decl, " ")
+	switch kind {
+	case "func", "const", "var":
+		return pkg, ident(decl), true
+
+	case "method":
+		recv, method, ok := strings.Cut(strings.TrimPrefix(decl, "("), ") ")
+		if !ok {
+			return pkg, "", true
+		}
+		return pkg, ident(strings.TrimPrefix(recv, "*")) + "." + ident(method), true
+
+	case "type":
+		typ := ident(decl)
+		_, member, ok := strings.Cut(decl, ", ")
+		if !ok {
+			return pkg, typ, true
+		}
+		if strings.HasPrefix(member, "embedded ") {
+			return pkg, "", true
+		}
+		return pkg, typ + "." + ident(member), true
+	}
+	return pkg, "", true
+}
+
+// ident returns the identifier at the start of s.
+func ident(s string) string {
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	if end == -1 {
+		return s
+	}
+	return s[:end]
+}
+
+func earliest(current, release string) string {
+	if current == "" || version.Compare(release, current) < 0 {
+		return release
+	}
+	return current
+}
@@ -0,0 +1,53 @@
+package goapi
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const go121 = `pkg bytes, func ContainsFunc([]uint8, func(int32) bool) bool #54386
+pkg bytes, method (*Buffer) AvailableBuffer() []uint8 #53685
+pkg cmp, func Compare[$0 Ordered]($0, $0) int #59488
+pkg crypto/cipher, type AEAD interface, NonceSize() int
+pkg crypto/tls, const QUICEncryptionLevelApplication = 3 #44886
+pkg syscall (freebsd-386), type SysProcAttr struct, Jail int #46259
+pkg log/slog, type Value struct #56345
+pkg log/slog, type Record struct, embedded Level
+`
+
+func TestParse(t *testing.T) {
+	v := New()
+	require.NoError(t, v.Parse(strings.NewReader(go121), "go1.21"))
+	require.NoError(t, v.Parse(strings.NewReader("pkg bytes, type Buffer struct\npkg crypto/cipher, type AEAD interface, NonceSize() int\n"), "go1"))
+
+	assert.Equal(t, "go1", v.Package("bytes"))
+	assert.Equal(t, "go1.21", v.Package("cmp"))
+	assert.Equal(t, "go1.21", v.Symbol("bytes", "ContainsFunc"))
+	assert.Equal(t, "go1.21", v.Symbol("bytes", "Buffer.AvailableBuffer"))
+	assert.Equal(t, "go1", v.Symbol("bytes", "Buffer"))
+	assert.Equal(t, "go1.21", v.Symbol("cmp", "Compare"))
+	assert.Equal(t, "go1", v.Symbol("crypto/cipher", "AEAD.NonceSize"))
+	assert.Equal(t, "go1.21", v.Symbol("crypto/tls", "QUICEncryptionLevelApplication"))
+	assert.Equal(t, "go1.21", v.Symbol("syscall", "SysProcAttr.Jail"))
+	assert.Equal(t, "go1.21", v.Symbol("log/slog", "Value"))
+	assert.Equal(t, "", v.Symbol("log/slog", "Record.Level"))
+	assert.Equal(t, "", v.Symbol("bytes", "Missing"))
+	assert.Equal(t, []string{"go1.21", "go1"}, v.Releases())
+}
+
+func TestLoad(t *testing.T) {
+	v, err := Load(filepath.Join(runtime.GOROOT(), "api"))
+	if err != nil {
+		t.Skip("GOROOT has no api directory")
+	}
+
+	assert.Equal(t, "go1.21", v.Package("slices"))
+	assert.Equal(t, "go1.21", v.Symbol("slices", "Contains"))
+	assert.Equal(t, "go1.16", v.Symbol("io", "ReadAll"))
+	assert.Equal(t, "go1", v.Symbol("fmt", "Println"))
+}
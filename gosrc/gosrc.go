@@ -0,0 +1,254 @@
+// Package gosrc loads the source files of Go packages, either from a local
+// GOROOT for the standard library, or from module zips served by a module
+// proxy.
+package gosrc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/build"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/DiscordGophers/dr-docso/proxy"
+)
+
+// ErrNotFound is returned when a package could not be found.
+var ErrNotFound = errors.New("package not found")
+
+// Package contains the Go source files of a single package.
+type Package struct {
+	// ImportPath is the import path of the package.
+	ImportPath string
+	// Module is the path of the module that provides the package. It is "std"
+	// for standard library packages.
+	Module string
+	// Version is the version of the module, or the Go release for the
+	// standard library, such as "go1.22.3".
+	Version string
+	// Dir is the directory of the package, relative to the module root.
+	// For the standard library, it is relative to GOROOT.
+	Dir string
+	// Files maps file names to their contents. Test files are not included.
+	Files map[string][]byte
+	// Subpackages are the import paths of the packages in subdirectories of
+	// the package, sorted. They are only set by loaders that read
+	// directories.
+	Subpackages []string
+}
+
+// Names returns the names of the package files, sorted.
+func (p *Package) Names() []string {
+	names := make([]string, 0, len(p.Files))
+	for name := range p.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Loader loads the source of a package. An empty version refers to the latest
+// version.
+type Loader interface {
+	Load(ctx context.Context, importPath, version string) (*Package, error)
+}
+
+// IsStdlib reports whether the import path belongs to the standard library,
+// which is the case when the first path element does not contain a dot.
+func IsStdlib(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// GOROOT loads standard library packages from a local Go installation.
+type GOROOT string
+
+// Version returns the Go release of the installation, as found in its
+// VERSION file.
+func (root GOROOT) Version() (string, error) {
+	data, err := os.ReadFile(filepath.Join(string(root), "VERSION"))
+	if err != nil {
+		return "", err
+	}
+	version, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSpace(version), nil
+}
+
+// Load loads a standard library package. Only the version of the installation
+// is available, other versions result in an error.
+func (root GOROOT) Load(ctx context.Context, importPath, version string) (*Package, error) {
+	current, err := root.Version()
+	if err != nil {
+		return nil, fmt.Errorf("could not read GOROOT version: %w", err)
+	}
+	if version != "" && version != current {
+		return nil, fmt.Errorf("only %s is available, not %s: %w", current, version, ErrNotFound)
+	}
+
+	pkg := &Package{
+		ImportPath: importPath,
+		Module:     "std",
+		Version:    current,
+		Dir:        path.Join("src", importPath),
+	}
+	if err := readDir(pkg, string(root)); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// Proxy loads packages from module zips downloaded from a module proxy.
+type Proxy struct {
+	Client *proxy.Client
+}
+
+// Load finds the module that provides the package, and extracts the package
+// files from the module zip.
+func (p Proxy) Load(ctx context.Context, importPath, version string) (*Package, error) {
+	mod, info, err := p.Client.Module(ctx, importPath, version)
+	if errors.Is(err, proxy.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	zr, err := p.Client.Zip(ctx, mod, info.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := strings.TrimPrefix(strings.TrimPrefix(importPath, mod), "/")
+	prefix := mod + "@" + info.Version + "/"
+	if dir != "" {
+		prefix += dir + "/"
+	}
+
+	pkg := &Package{
+		ImportPath: importPath,
+		Module:     mod,
+		Version:    info.Version,
+		Dir:        dir,
+		Files:      map[string][]byte{},
+	}
+	subs := map[string]bool{}
+	for _, f := range zr.File {
+		name, ok := strings.CutPrefix(f.Name, prefix)
+		if !ok || !isSource(name) {
+			continue
+		}
+		if sub, _ := path.Split(name); sub != "" {
+			if !ignoredDir(sub) {
+				subs[importPath+"/"+strings.TrimSuffix(sub, "/")] = true
+			}
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		pkg.Files[name] = data
+	}
+	if len(pkg.Files) == 0 {
+		return nil, ErrNotFound
+	}
+	pkg.Subpackages = sortedKeys(subs)
+	return pkg, nil
+}
+
+// Cache caches the packages returned by a loader. When full, the least
+// recently loaded package is evicted.
+type Cache struct {
+	loader Loader
+	size   int
+
+	mu    sync.Mutex
+	order []string
+	pkgs  map[string]*Package
+}
+
+// NewCache creates a cache that keeps up to size packages.
+func NewCache(loader Loader, size int) *Cache {
+	return &Cache{
+		loader: loader,
+		size:   size,
+		pkgs:   map[string]*Package{},
+	}
+}
+
+// Load returns the cached package, or loads it if it is not cached.
+func (c *Cache) Load(ctx context.Context, importPath, version string) (*Package, error) {
+	key := importPath + "@" + version
+
+	c.mu.Lock()
+	pkg, ok := c.pkgs[key]
+	c.mu.Unlock()
+	if ok {
+		return pkg, nil
+	}
+
+	pkg, err := c.loader.Load(ctx, importPath, version)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.pkgs[key]; !ok {
+		c.order = append(c.order, key)
+	}
+	c.pkgs[key] = pkg
+	for len(c.order) > c.size {
+		delete(c.pkgs, c.order[0])
+		c.order = c.order[1:]
+	}
+	return pkg, nil
+}
+
+// Split loads standard library packages with Std, and all other packages
+// with Mod.
+type Split struct {
+	Std Loader
+	Mod Loader
+}
+
+// Load loads the package with the matching loader.
+func (s Split) Load(ctx context.Context, importPath, version string) (*Package, error) {
+	if IsStdlib(importPath) {
+		return s.Std.Load(ctx, importPath, version)
+	}
+	return s.Mod.Load(ctx, importPath, version)
+}
+
+// MatchFile reports whether the file would be built on linux/amd64, which is
+// the platform that pkg.go.dev shows documentation for by default. cgo
+// reports whether files that require cgo are included.
+func MatchFile(name string, content []byte, cgo bool) bool {
+	ctxt := build.Default
+	ctxt.GOOS = "linux"
+	ctxt.GOARCH = "amd64"
+	ctxt.CgoEnabled = cgo
+	ctxt.OpenFile = func(string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+
+	ok, err := ctxt.MatchFile(".", name)
+	return err == nil && ok
+}
+
+func isSource(name string) bool {
+	return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go")
+}
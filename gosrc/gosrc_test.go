@@ -0,0 +1,141 @@
+package gosrc
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/DiscordGophers/dr-docso/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyLoad(t *testing.T) {
+	dir := t.TempDir()
+	v := filepath.Join(dir, "example.com", "mod", "@v")
+	require.NoError(t, os.MkdirAll(v, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "example.com", "mod", "@latest"), []byte(`{"Version":"v1.0.0"}`), 0o644))
+
+	f, err := os.Create(filepath.Join(v, "v1.0.0.zip"))
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	for _, name := range []string{"go.mod", "mod.go", "sub/sub.go", "sub/sub_test.go", "sub/deeper/deeper.go", "sub/testdata/x.go"} {
+		w, err := zw.Create("example.com/mod@v1.0.0/" + name)
+		require.NoError(t, err)
+		w.Write([]byte("package x\n"))
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	loader := Proxy{Client: proxy.New("file://"+dir, nil, "")}
+
+	pkg, err := loader.Load(context.Background(), "example.com/mod/sub", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/mod", pkg.Module)
+	assert.Equal(t, "v1.0.0", pkg.Version)
+	assert.Equal(t, "sub", pkg.Dir)
+	assert.Equal(t, []string{"sub.go"}, pkg.Names())
+	assert.Equal(t, []string{"example.com/mod/sub/deeper"}, pkg.Subpackages)
+
+	pkg, err = loader.Load(context.Background(), "example.com/mod", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mod.go"}, pkg.Names())
+
+	_, err = loader.Load(context.Background(), "example.com/other", "")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestGOROOTLoad(t *testing.T) {
+	root := GOROOT(runtime.GOROOT())
+	if _, err := root.Version(); err != nil {
+		t.Skip("GOROOT has no VERSION file")
+	}
+
+	pkg, err := root.Load(context.Background(), "errors", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "std", pkg.Module)
+	assert.Equal(t, "src/errors", pkg.Dir)
+	assert.Contains(t, pkg.Files, "errors.go")
+
+	pkg, err = root.Load(context.Background(), "net", "")
+	assert.NoError(t, err)
+	assert.Contains(t, pkg.Subpackages, "net/http/httptest")
+	assert.NotContains(t, pkg.Subpackages, "net/http/testdata")
+
+	_, err = root.Load(context.Background(), "errors", "go1.0")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+// writeFiles writes the files, which are given as pairs of names and contents,
+// to the directory.
+func writeFiles(t *testing.T, dir string, files ...string) {
+	for i := 0; i < len(files); i += 2 {
+		name := filepath.Join(dir, filepath.FromSlash(files[i]))
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+		require.NoError(t, os.WriteFile(name, []byte(files[i+1]), 0o644))
+	}
+}
+
+func TestDirLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir,
+		"go.mod", "module example.com/mod\n",
+		"mod.go", "package mod\n",
+		"sub/sub.go", "package sub\n",
+		"sub/testdata/x.go", "package x\n",
+		"nested/go.mod", "module example.com/mod/nested\n",
+		"nested/nested.go", "package nested\n",
+	)
+
+	pkg, err := Dir(dir).Load(context.Background(), "example.com/mod", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/mod", pkg.Module)
+	assert.Equal(t, []string{"mod.go"}, pkg.Names())
+	assert.Equal(t, []string{"example.com/mod/sub"}, pkg.Subpackages)
+
+	_, err = Dir(dir).Load(context.Background(), "example.com/other", "")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	_, err = Dir(dir).Load(context.Background(), "example.com/mod", "v1.0.0")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestModCacheLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir,
+		"example.com/!mod@v1.0.0/mod.go", "package mod\n",
+		"example.com/!mod@v1.2.0/mod.go", "package mod\n",
+		"example.com/!mod@v1.2.0/sub/sub.go", "package sub\n",
+	)
+	other := t.TempDir()
+	writeFiles(t, other, "go.mod", "module example.com/other\n", "other.go", "package other\n")
+	cache := Chain{Dir(other), ModCache(dir)}
+
+	pkg, err := cache.Load(context.Background(), "example.com/Mod", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.2.0", pkg.Version)
+	assert.Equal(t, []string{"example.com/Mod/sub"}, pkg.Subpackages)
+
+	pkg, err = cache.Load(context.Background(), "example.com/Mod/sub", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/Mod", pkg.Module)
+	assert.Equal(t, "sub", pkg.Dir)
+
+	pkg, err = cache.Load(context.Background(), "example.com/Mod", "v1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.0.0", pkg.Version)
+
+	_, err = cache.Load(context.Background(), "example.com/Mod", "v2.0.0")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestMatchFile(t *testing.T) {
+	assert.True(t, MatchFile("file.go", []byte("package x\n"), true))
+	assert.True(t, MatchFile("file_linux.go", []byte("package x\n"), true))
+	assert.False(t, MatchFile("file_windows.go", []byte("package x\n"), true))
+	assert.False(t, MatchFile("file.go", []byte("//go:build windows\n\npackage x\n"), true))
+	assert.False(t, MatchFile("file.go", []byte("//go:build cgo\n\npackage x\n"), false))
+}
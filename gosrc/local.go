@@ -0,0 +1,229 @@
+package gosrc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Dir loads packages from a module in a local directory, such as a checkout
+// of its repository. The module path is read from its go.mod file. Only the
+// version in the directory is available, so other versions are not found.
+type Dir string
+
+// Load loads a package of the module in the directory.
+func (d Dir) Load(ctx context.Context, importPath, version string) (*Package, error) {
+	if version != "" {
+		return nil, fmt.Errorf("only the local version of %s is available: %w", d, ErrNotFound)
+	}
+
+	data, err := os.ReadFile(filepath.Join(string(d), "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	mod := modfile.ModulePath(data)
+	if mod == "" {
+		return nil, fmt.Errorf("%s: go.mod has no module path", d)
+	}
+
+	dir, ok := moduleDir(mod, importPath)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	pkg := &Package{
+		ImportPath: importPath,
+		Module:     mod,
+		Dir:        dir,
+	}
+	return pkg, readDir(pkg, string(d))
+}
+
+// ModCache loads packages from the extracted modules in a module cache, such
+// as $GOPATH/pkg/mod. Like Proxy, the longest module path that provides the
+// package is used. If version is empty, the latest version in the cache is
+// used.
+type ModCache string
+
+// Load finds the module that provides the package in the cache, and loads the
+// package from its directory.
+func (c ModCache) Load(ctx context.Context, importPath, version string) (*Package, error) {
+	for mod := importPath; strings.Contains(mod, "/"); mod = path.Dir(mod) {
+		v, root, err := c.module(mod, version)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		dir, _ := moduleDir(mod, importPath)
+		pkg := &Package{
+			ImportPath: importPath,
+			Module:     mod,
+			Version:    v,
+			Dir:        dir,
+		}
+		err = readDir(pkg, root)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		return pkg, err
+	}
+	return nil, ErrNotFound
+}
+
+// module returns the version of the module in the cache, and its directory.
+func (c ModCache) module(mod, version string) (string, string, error) {
+	escaped, err := module.EscapePath(mod)
+	if err != nil {
+		return "", "", ErrNotFound
+	}
+	dir, base := filepath.Split(filepath.Join(string(c), filepath.FromSlash(escaped)))
+
+	if version == "" {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", "", ErrNotFound
+		}
+		if err != nil {
+			return "", "", err
+		}
+		for _, entry := range entries {
+			v, ok := strings.CutPrefix(entry.Name(), base+"@")
+			if !ok || !entry.IsDir() {
+				continue
+			}
+			if v, err = module.UnescapeVersion(v); err == nil && semver.Compare(v, version) > 0 {
+				version = v
+			}
+		}
+		if version == "" {
+			return "", "", ErrNotFound
+		}
+	}
+
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return "", "", ErrNotFound
+	}
+	root := filepath.Join(dir, base+"@"+escapedVersion)
+	if _, err := os.Stat(root); errors.Is(err, fs.ErrNotExist) {
+		return "", "", ErrNotFound
+	} else if err != nil {
+		return "", "", err
+	}
+	return version, root, nil
+}
+
+// Chain loads packages with the first loader that finds them.
+type Chain []Loader
+
+// Load tries each loader in order. Errors other than ErrNotFound are returned
+// right away.
+func (c Chain) Load(ctx context.Context, importPath, version string) (*Package, error) {
+	for _, loader := range c {
+		pkg, err := loader.Load(ctx, importPath, version)
+		if !errors.Is(err, ErrNotFound) {
+			return pkg, err
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// moduleDir returns the directory of the package in the module, relative to
+// the module root. ok is false if the module does not contain the package.
+func moduleDir(mod, importPath string) (dir string, ok bool) {
+	if importPath == mod {
+		return "", true
+	}
+	return strings.CutPrefix(importPath, mod+"/")
+}
+
+// readDir reads the source files of the package from its directory under
+// root, and finds its sub-packages. Directories that the go command ignores,
+// such as testdata, vendor and nested modules, are skipped.
+func readDir(pkg *Package, root string) error {
+	dir := filepath.Join(root, filepath.FromSlash(pkg.Dir))
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	pkg.Files = map[string][]byte{}
+	for _, entry := range entries {
+		if entry.IsDir() || !isSource(entry.Name()) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		pkg.Files[entry.Name()] = data
+	}
+	if len(pkg.Files) == 0 {
+		return ErrNotFound
+	}
+
+	subs := map[string]bool{}
+	err = filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil || p == dir {
+			return err
+		}
+
+		name := entry.Name()
+		if entry.IsDir() {
+			if ignoredDir(name) {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if parent := filepath.Dir(p); parent != dir && isSource(name) {
+			rel, _ := filepath.Rel(dir, parent)
+			subs[pkg.ImportPath+"/"+filepath.ToSlash(rel)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	pkg.Subpackages = sortedKeys(subs)
+	return nil
+}
+
+// ignoredDir reports whether the go command ignores packages in the
+// directory, which is a slash separated path relative to a package.
+func ignoredDir(dir string) bool {
+	for _, name := range strings.Split(strings.Trim(dir, "/"), "/") {
+		if name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
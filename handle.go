@@ -8,21 +8,39 @@ import (
 	"strings"
 
 	"github.com/DiscordGophers/dr-docso/blog"
+	"github.com/DiscordGophers/dr-docso/goapi"
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	"github.com/DiscordGophers/dr-docso/proxy"
+	"github.com/DiscordGophers/dr-docso/typecheck"
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
 	"github.com/diamondburned/arikawa/v3/state"
 	"github.com/diamondburned/arikawa/v3/utils/httputil"
-	"github.com/hhhapz/doc"
 )
 
 type botState struct {
 	cfg      configuration
 	appID    discord.AppID
-	searcher doc.CachedSearcher
+	searcher *searchCache
+	backends *fallbackSearcher
+	proxy    *proxy.Client
+	sources  gosrc.Loader
+	types    *typecheck.Checker
+	symbols  *symbolIndex
+	api      *goapi.Versions
 	state    *state.State
 
 	articles []blog.Article
+
+	// missingModules and missingSymbols remember the modules, and the
+	// symbols in modules, that were not found.
+	missingModules *missingCache
+	missingSymbols *missingCache
+
+	usage   *usage
+	prewarm *prewarmer
+	modules *moduleIndex
 }
 
 func (b *botState) OnCommand(e *gateway.InteractionCreateEvent) {
@@ -67,6 +85,7 @@ func (b *botState) OnCommand(e *gateway.InteractionCreateEvent) {
 		case "blog":
 			b.handleBlogComponent(e, data, split[1])
 		case "docs":
+			b.handleDocsSubcomponent(e, data, split[1])
 		case "spec":
 			b.handleSpecComponent(e, data, split[1])
 		case "info":
@@ -76,9 +95,9 @@ func (b *botState) OnCommand(e *gateway.InteractionCreateEvent) {
 }
 
 var (
-	cmdre    = regexp.MustCompile(`\$\[([\w\d/.]+)\]`)
-	urlre    = regexp.MustCompile(`^(https?://)?pkg.go.dev/([\w\d/.#]+)$`)
-	escURLre = regexp.MustCompile(`<(https?://)?pkg.go.dev/([\w\d/.#]+)>`)
+	cmdre    = regexp.MustCompile(`\$\[([\w\d/.@ -]+)\]`)
+	urlre    = regexp.MustCompile(`^(https?://)?pkg.go.dev/([\w\d/.#@-]+)$`)
+	escURLre = regexp.MustCompile(`<(https?://)?pkg.go.dev/([\w\d/.#@-]+)>`)
 )
 
 func (b *botState) OnMessage(m *gateway.MessageCreateEvent) {
@@ -90,6 +109,11 @@ func (b *botState) OnMessage(m *gateway.MessageCreateEvent) {
 		return
 	}
 
+	if name, args, ok := parsePrefixCommand(m.Content, b.cfg.prefix(m.GuildID)); ok {
+		b.handlePrefix(m, name, args)
+		return
+	}
+
 	var queries []textQuery
 	for _, v := range cmdre.FindAllStringSubmatch(m.Content, 3) {
 		queries = append(queries, textQuery{v[1], "cmdre"})
@@ -140,15 +164,33 @@ var commands = []api.CreateCommandData{
 		Options: []discord.CommandOption{
 			&discord.StringOption{
 				OptionName:   "module",
-				Description:  "Module name",
+				Description:  "Module name, leave empty to search all packages",
 				Autocomplete: true,
-				Required:     true,
 			},
 			&discord.StringOption{
 				OptionName:   "item",
 				Description:  "Search item in module",
 				Autocomplete: true,
-				Required:     true,
+			},
+			&discord.StringOption{
+				OptionName:  "from",
+				Description: "Old module version, when using diff",
+			},
+			&discord.StringOption{
+				OptionName:  "to",
+				Description: "New module version, when using diff (default latest)",
+			},
+			&discord.StringOption{
+				OptionName:  "version",
+				Description: "Your Go version, to warn about newer standard library symbols",
+			},
+			&discord.StringOption{
+				OptionName:  "text",
+				Description: "Text to find in doc comments, when using search",
+			},
+			&discord.BooleanOption{
+				OptionName:  "stdlib",
+				Description: "Search the whole standard library, when using search",
 			},
 		},
 	},
@@ -223,6 +265,45 @@ var commands = []api.CreateCommandData{
 						OptionName:  "prune",
 						Description: "Prune package cache not used in over 24 hours",
 					},
+					{
+						OptionName:  "missing",
+						Description: "List the modules and symbols remembered as not found",
+					},
+					{
+						OptionName:  "forget",
+						Description: "Forget modules and symbols remembered as not found",
+						Options: []discord.CommandOptionValue{
+							&discord.StringOption{
+								OptionName:  "query",
+								Description: "Module or symbol name, leave empty to forget all",
+							},
+						},
+					},
+				},
+			},
+			&discord.SubcommandGroupOption{
+				OptionName:  "prefix",
+				Description: "Configure the prefix of text commands in this server",
+				Subcommands: []*discord.SubcommandOption{
+					{
+						OptionName:  "set",
+						Description: "Set the prefix",
+						Options: []discord.CommandOptionValue{
+							&discord.StringOption{
+								OptionName:  "prefix",
+								Description: "Prefix, such as dr.",
+								Required:    true,
+							},
+						},
+					},
+					{
+						OptionName:  "disable",
+						Description: "Disable text commands",
+					},
+					{
+						OptionName:  "reset",
+						Description: "Use the default prefix",
+					},
 				},
 			},
 			&discord.SubcommandGroupOption{
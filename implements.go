@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/types"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	"github.com/DiscordGophers/dr-docso/typecheck"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/hhhapz/doc"
+)
+
+const (
+	implementsTimeout   = 3 * time.Second
+	implementersTimeout = 30 * time.Second
+
+	// typecheckPackages is the number of type-checked packages that are
+	// kept, which leaves room for the standard library and the packages of
+	// the docs cache.
+	typecheckPackages = 512
+
+	notInterface = "`%s` is not an interface type."
+	typeCheckErr = "Could not type-check `%s`, please try again later."
+)
+
+// wellKnownInterfaces are the interfaces listed in the Implements field of
+// type embeds.
+var wellKnownInterfaces = []string{
+	"error",
+	"fmt.Stringer",
+	"fmt.Formatter",
+	"io.Reader",
+	"io.Writer",
+	"io.Closer",
+	"io.Seeker",
+	"io.ReaderAt",
+	"io.ReaderFrom",
+	"io.WriterTo",
+	"sort.Interface",
+	"container/heap.Interface",
+	"context.Context",
+	"hash.Hash",
+	"flag.Value",
+	"encoding.TextMarshaler",
+	"encoding.TextUnmarshaler",
+	"encoding.BinaryMarshaler",
+	"encoding.BinaryUnmarshaler",
+	"encoding/json.Marshaler",
+	"encoding/json.Unmarshaler",
+	"database/sql.Scanner",
+	"database/sql/driver.Valuer",
+	"io/fs.FS",
+	"net.Conn",
+	"net/http.Handler",
+	"net/http.RoundTripper",
+}
+
+// implementersQuery reports whether the query asks for the implementers of an
+// interface, such as "io.Reader implementers", and returns the query of the
+// interface.
+func implementersQuery(query string) (string, bool) {
+	query = strings.TrimSpace(query)
+	for _, sep := range []string{" ", "."} {
+		suffix := sep + "implementers"
+		if len(query) > len(suffix) && strings.EqualFold(query[len(query)-len(suffix):], suffix) {
+			return strings.TrimSpace(query[:len(query)-len(suffix)]), true
+		}
+	}
+	return "", false
+}
+
+// typeObject type-checks the package of a type symbol, and returns the type
+// name it refers to.
+func (b *botState) typeObject(ctx context.Context, sym symbol) (*types.TypeName, error) {
+	importPath, version := sym.importPath()
+	if version == "latest" {
+		version = ""
+	}
+
+	pkg, err := b.types.Check(ctx, importPath, version)
+	if err != nil {
+		return nil, err
+	}
+	return typeName(pkg, sym)
+}
+
+// typeName returns the type name that a type symbol refers to in its
+// type-checked package.
+func typeName(pkg *types.Package, sym symbol) (*types.TypeName, error) {
+	obj, ok := pkg.Scope().Lookup(sym.typ.Name).(*types.TypeName)
+	if !ok {
+		return nil, gosrc.ErrNotFound
+	}
+	return obj, nil
+}
+
+// implements returns the well-known interfaces that the type of the symbol
+// implements. As type-checking a package for the first time can be slow, nil
+// is returned if it takes longer than implementsTimeout, or if ctx does not
+// leave that much time, as is the case for component responses. Then only
+// packages that are already type-checked are used, and the package is
+// type-checked in the background, so that it is available the next time.
+func (b *botState) implements(ctx context.Context, sym symbol) []string {
+	if sym.kind != kindType {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < implementsTimeout {
+		importPath, version := sym.importPath()
+		if version == "latest" {
+			version = ""
+		}
+		if pkg, ok := b.types.Checked(importPath, version); ok {
+			if obj, err := typeName(pkg, sym); err == nil {
+				return implementsOf(obj, b.types.LookupChecked)
+			}
+			return nil
+		}
+		go b.checkImplements(sym)
+		return nil
+	}
+
+	ch := make(chan []string, 1)
+	go func() {
+		ch <- b.checkImplements(sym)
+	}()
+
+	select {
+	case impls := <-ch:
+		return impls
+	case <-time.After(implementsTimeout):
+		return nil
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// checkImplements type-checks the package of the type symbol, and the
+// well-known interfaces, for up to fetchTimeout, and returns the interfaces
+// that it implements.
+func (b *botState) checkImplements(sym symbol) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	obj, err := b.typeObject(ctx, sym)
+	if err != nil {
+		log.Printf("Could not type-check %s: %v", sym.title(), err)
+		return nil
+	}
+	return implementsOf(obj, func(name string) (types.Object, error) {
+		return b.types.Lookup(ctx, name)
+	})
+}
+
+// implementsOf returns the well-known interfaces that the type implements,
+// looking them up with lookup. Interfaces that cannot be looked up are
+// skipped.
+func implementsOf(obj *types.TypeName, lookup func(name string) (types.Object, error)) []string {
+	named, ok := obj.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		return nil
+	}
+
+	var impls []string
+	for _, name := range wellKnownInterfaces {
+		ifaceObj, err := lookup(name)
+		if err != nil || ifaceObj == obj {
+			continue
+		}
+		iface, ok := ifaceObj.Type().Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+
+		ok, ptr := typecheck.Implements(named, iface)
+		if !ok {
+			continue
+		}
+		impl := "`" + path.Base(name) + "`"
+		if ptr {
+			impl += " (*" + obj.Name() + ")"
+		}
+		impls = append(impls, impl)
+	}
+	return impls
+}
+
+// implementersEmbed lists the types of the standard library, and of the
+// cached packages, that implement the interface of the query.
+func (b *botState) implementersEmbed(ctx context.Context, user discord.User, query string) discord.Embed {
+	sym, err := b.lookup(ctx, user, query)
+	if err != nil {
+		var lerr lookupError
+		if errors.As(err, &lerr) {
+			return failEmbed(lerr.title, lerr.msg)
+		}
+		return failEmbed("Error", err.Error())
+	}
+	if sym.kind != kindType {
+		return failEmbed("Error", fmt.Sprintf(notInterface, query))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, implementersTimeout)
+	defer cancel()
+
+	obj, err := b.typeObject(ctx, sym)
+	if err != nil {
+		log.Printf("Implementers request by %s(%q) failed: %v", user.Tag(), query, err)
+		return failEmbed("Error", fmt.Sprintf(typeCheckErr, query))
+	}
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return failEmbed("Error", fmt.Sprintf(notInterface, query))
+	}
+
+	var impls []string
+	var searched int
+	for _, importPath := range b.implementerPackages(sym) {
+		if ctx.Err() != nil {
+			break
+		}
+		pkg, err := b.types.Check(ctx, importPath, "")
+		if err != nil {
+			continue
+		}
+		searched++
+		for _, impl := range typecheck.Implementers(pkg, iface) {
+			impls = append(impls, impl.String())
+		}
+	}
+
+	var sb strings.Builder
+	for i, impl := range impls {
+		line := "`" + impl + "`\n"
+		if sb.Len()+len(line) > 3900 {
+			fmt.Fprintf(&sb, "*...and %d more*", len(impls)-i)
+			break
+		}
+		sb.WriteString(line)
+	}
+	if len(impls) == 0 {
+		sb.WriteString("*No implementations found.*")
+	}
+
+	footer := fmt.Sprintf("%d implementations in %d packages", len(impls), searched)
+	if ctx.Err() != nil {
+		footer += " (timed out)"
+	}
+	return discord.Embed{
+		Title:       "Implementers: " + sym.title(),
+		URL:         fmt.Sprintf("https://pkg.go.dev/%s#%s", sym.pkg.URL, sym.typ.Name),
+		Description: sb.String(),
+		Footer:      &discord.EmbedFooter{Text: footer},
+		Color:       accentColor,
+	}
+}
+
+// implementerPackages returns the packages that are searched for
+// implementations: the package of the interface, the standard library, and
+// the packages in the docs cache.
+func (b *botState) implementerPackages(sym symbol) []string {
+	own, _ := sym.importPath()
+	packages := map[string]bool{own: true}
+	for lib := range stdlib {
+		if skipStdlib(lib) {
+			continue
+		}
+		packages[lib] = true
+	}
+
+	b.searcher.WithCache(func(cache map[string]*doc.CachedPackage) {
+		for _, pkg := range cache {
+			name, version := splitVersion(pkg.URL)
+			if version == "" && !gosrc.IsStdlib(name) {
+				packages[name] = true
+			}
+		}
+	})
+
+	paths := make([]string, 0, len(packages))
+	for p := range packages {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// skipStdlib reports whether the standard library package is not searched for
+// implementations, as it is not importable or only used by the toolchain.
+func skipStdlib(importPath string) bool {
+	switch importPath {
+	case "builtin", "unsafe", "cmd":
+		return true
+	}
+	return strings.HasPrefix(importPath, "cmd/") ||
+		strings.Contains(importPath, "internal") ||
+		strings.HasPrefix(importPath, "vendor/")
+}
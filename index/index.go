@@ -0,0 +1,109 @@
+// Package index implements a client for the Go module index, which lists the
+// module versions that were published to a module proxy, as served by
+// https://index.golang.org.
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultURL is the module index used when none is configured.
+const DefaultURL = "https://index.golang.org"
+
+// MaxLimit is the largest number of versions that the index returns at once.
+const MaxLimit = 2000
+
+// Version is a module version in the index.
+type Version struct {
+	Path      string
+	Version   string
+	Timestamp time.Time
+}
+
+// StatusError is returned when the index responds with an unexpected status.
+type StatusError struct {
+	URL    string
+	Status int
+}
+
+func (err StatusError) Error() string {
+	return fmt.Sprintf("%s: invalid response status: %d", err.URL, err.Status)
+}
+
+// Client queries a module index.
+type Client struct {
+	base   string
+	client *http.Client
+	agent  string
+}
+
+// New creates a client for the index at base, such as DefaultURL. If base is
+// empty, DefaultURL is used.
+func New(base string, client *http.Client, agent string) *Client {
+	if base == "" {
+		base = DefaultURL
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Client{
+		base:   strings.TrimSuffix(base, "/"),
+		client: client,
+		agent:  agent,
+	}
+}
+
+// Versions returns up to limit versions that were published at or after
+// since, oldest first. To read the whole index, call it again with the
+// timestamp of the last version, until fewer than limit versions are
+// returned. As versions can share a timestamp, the next page may start with
+// versions that were already returned.
+func (c *Client) Versions(ctx context.Context, since time.Time, limit int) ([]Version, error) {
+	q := url.Values{}
+	if !since.IsZero() {
+		q.Set("since", since.UTC().Format(time.RFC3339Nano))
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(min(limit, MaxLimit)))
+	}
+	u := c.base + "/index?" + q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	if c.agent != "" {
+		req.Header.Set("User-Agent", c.agent)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, StatusError{URL: u, Status: resp.StatusCode}
+	}
+
+	var versions []Version
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var v Version
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			return versions, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", u, err)
+		}
+		versions = append(versions, v)
+	}
+}
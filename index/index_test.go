@@ -0,0 +1,39 @@
+package index
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersions(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index" {
+			http.NotFound(w, r)
+			return
+		}
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"Path":"github.com/gin-gonic/gin","Version":"v1.9.1","Timestamp":"2023-06-05T10:00:00Z"}
+{"Path":"golang.org/x/mod","Version":"v0.17.0","Timestamp":"2023-06-05T10:00:01.5Z"}
+`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL+"/", nil, "test")
+	since := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	versions, err := c.Versions(context.Background(), since, 5000)
+	assert.NoError(t, err)
+	assert.Equal(t, "limit=2000&since=2023-06-05T00%3A00%3A00Z", query)
+	assert.Equal(t, []Version{
+		{"github.com/gin-gonic/gin", "v1.9.1", time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC)},
+		{"golang.org/x/mod", "v0.17.0", time.Date(2023, 6, 5, 10, 0, 1, 5e8, time.UTC)},
+	}, versions)
+
+	_, err = New(srv.URL+"/missing", nil, "").Versions(context.Background(), time.Time{}, 0)
+	assert.Equal(t, StatusError{URL: srv.URL + "/missing/index?", Status: 404}, err)
+}
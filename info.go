@@ -11,7 +11,6 @@ import (
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
 	"github.com/dustin/go-humanize"
-	"github.com/hhhapz/doc"
 )
 
 var started = time.Now().Unix()
@@ -19,13 +18,19 @@ var started = time.Now().Unix()
 func (b *botState) handleInfo(e *gateway.InteractionCreateEvent, _ *discord.CommandInteraction) {
 	log.Printf("%s used info", e.User.Tag())
 
+	b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+		Type: api.MessageInteractionWithSource,
+		Data: b.infoResponse(),
+	})
+}
+
+// infoResponse returns the bot information, which is shared by the slash and
+// text commands.
+func (b *botState) infoResponse() *api.InteractionResponseData {
 	stats := runtime.MemStats{}
 	runtime.ReadMemStats(&stats)
 
-	var items int
-	b.searcher.WithCache(func(cache map[string]*doc.CachedPackage) {
-		items = len(cache)
-	})
+	items, size, evicted, pruned := b.searcher.stats()
 
 	buf := &bytes.Buffer{}
 
@@ -34,65 +39,73 @@ func (b *botState) handleInfo(e *gateway.InteractionCreateEvent, _ *discord.Comm
 	fmt.Fprintf(buf, "Memory: %s / %s (alloc / sys)\n", humanize.Bytes(stats.Alloc), humanize.Bytes(stats.Sys))
 	fmt.Fprintf(buf, "Source: %s\n", "[link](https://github.com/DiscordGophers/dr-docso)")
 	fmt.Fprintf(buf, "Concurrent Tasks: %s\n", humanize.Comma(int64(runtime.NumGoroutine())))
-	fmt.Fprintf(buf, "Cached Entries: %s\n\n", humanize.Comma(int64(items)))
+	fmt.Fprintf(buf, "Cached Entries: %s (%s)\n", humanize.Comma(int64(items)), humanize.Bytes(uint64(size)))
+	fmt.Fprintf(buf, "Cache Evictions: %s (full) / %s (unused)\n\n", humanize.Comma(evicted), humanize.Comma(pruned))
+	fmt.Fprintf(buf, "Backends:\n%s\n\n", b.backends.stats())
+	if b.prewarm != nil {
+		fmt.Fprintf(buf, "Prewarm: %s\n\n", b.prewarm.status())
+	}
 	fmt.Fprintf(buf, "Maintained by: %s\n", "[hhhapz#8936](https://github.com/hhhapz)")
 	fmt.Fprintf(buf, "Hosted on %s by %s!\n", "[TransIP](https://www.transip.nl/)", "[Sgt_Tailor#0124](https://github.com/svenwiltink)")
 
-	b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
-		Type: api.MessageInteractionWithSource,
-		Data: &api.InteractionResponseData{
-			Flags: api.EphemeralResponse,
-			Embeds: &[]discord.Embed{{
-				Title:       "Dr-Docso",
-				Description: buf.String(),
-				Color:       accentColor,
-			}},
-			Components: &discord.ContainerComponents{
-				&discord.ActionRowComponent{
-					&discord.ButtonComponent{
-						Label:    "Command Info",
-						CustomID: "info.help",
-						Style:    discord.SecondaryButtonStyle(),
-					},
+	return &api.InteractionResponseData{
+		Flags: api.EphemeralResponse,
+		Embeds: &[]discord.Embed{{
+			Title:       "Dr-Docso",
+			Description: buf.String(),
+			Color:       accentColor,
+		}},
+		Components: &discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.ButtonComponent{
+					Label:    "Command Info",
+					CustomID: "info.help",
+					Style:    discord.SecondaryButtonStyle(),
 				},
 			},
 		},
-	})
+	}
 }
 
 func (b *botState) handleInfoComponent(e *gateway.InteractionCreateEvent, data discord.ComponentInteraction, cmd string) {
 	switch cmd {
 	case "help":
+		fields := []discord.EmbedField{
+			{
+				Name:  "/docs",
+				Value: "Query Go package documentation.\nSee options in autocomplete.",
+			},
+		}
+		if prefix := b.cfg.prefix(e.GuildID); prefix != "" {
+			fields = append(fields, discord.EmbedField{
+				Name:  prefix + "docs <query>",
+				Value: fmt.Sprintf("Query Go package documentation.\n*Text command version, `%[1]sspec`, `%[1]sblog` and `%[1]sinfo` work too.*", prefix),
+			})
+		}
+		fields = append(fields,
+			discord.EmbedField{
+				Name:  "/blog <slug|query>",
+				Value: "Query [Go Blog](https://go.dev/blog) articles.",
+			},
+			discord.EmbedField{
+				Name:  "/info",
+				Value: "Bot Information.",
+			},
+			discord.EmbedField{
+				Name:  "/config",
+				Value: "Configure dr-docso.\n*(Herders only)*",
+			},
+		)
+
 		b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
 			Type: api.MessageInteractionWithSource,
 			Data: &api.InteractionResponseData{
 				Flags: api.EphemeralResponse,
 				Embeds: &[]discord.Embed{
 					{
-						Title: fmt.Sprintf("Command Help"),
-						Fields: []discord.EmbedField{
-							{
-								Name:  "/docs",
-								Value: "Query Go package documentation.\nSee options in autocomplete.",
-							},
-							{
-								Name:  "d.docs <module> [item]",
-								Value: "Query Go package documentation.\n*Text commeand version.*",
-							},
-							{
-								Name:  "/blog <slug|query>",
-								Value: "Query [Go Blog](https://go.dev/blog) articles.",
-							},
-							{
-								Name:  "/info",
-								Value: "Bot Information.",
-							},
-							{
-								Name:  "/config",
-								Value: "Configure dr-docso.\n*(Herders only)*",
-							},
-						},
-						Color: accentColor,
+						Title:  fmt.Sprintf("Command Help"),
+						Fields: fields,
+						Color:  accentColor,
 					},
 				},
 			},
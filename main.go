@@ -4,8 +4,17 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/DiscordGophers/dr-docso/goapi"
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	"github.com/DiscordGophers/dr-docso/index"
+	"github.com/DiscordGophers/dr-docso/proxy"
+	"github.com/DiscordGophers/dr-docso/srcdoc"
+	"github.com/DiscordGophers/dr-docso/typecheck"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
 	"github.com/diamondburned/arikawa/v3/state"
@@ -22,11 +31,58 @@ func run() error {
 	}
 
 	s := state.New("Bot " + cfg.Token)
-	searcher := doc.NewCachedSearcher(pkgsite.Parser, doc.UserAgent(userAgent), doc.WithDuplicateTypeFuncs())
+	modules := proxy.New(cfg.proxy(), http.DefaultClient, userAgent)
+	sources := gosrc.Split{
+		Std: gosrc.GOROOT(cfg.goroot()),
+		Mod: gosrc.Proxy{Client: modules},
+	}
+	// Module zips are large, so the loaded packages are shared by the
+	// proxy backend, source code and type-checking.
+	loader := gosrc.NewCache(sources, 32)
+	backends, err := newBackends(cfg, sources, loader)
+	if err != nil {
+		return err
+	}
+	cacheSize, err := cfg.cacheSize()
+	if err != nil {
+		return err
+	}
+	moduleTTL, symbolTTL, err := cfg.missingTTLs()
+	if err != nil {
+		return err
+	}
+	missingModules := newMissingCache(moduleTTL)
+	interval, err := cfg.prewarmInterval()
+	if err != nil {
+		return err
+	}
+	api, err := goapi.Load(filepath.Join(cfg.goroot(), "api"))
+	if err != nil {
+		log.Printf("Could not load the Go API files: %v", err)
+		api = goapi.New()
+	}
+
+	// Missing modules are remembered below the cache, so that the searches
+	// of a missing module are coalesced too.
+	searcher := &missingSearcher{Searcher: backends, missing: missingModules}
+
 	b := botState{
-		cfg:      cfg,
-		searcher: searcher,
-		state:    s,
+		cfg:            cfg,
+		searcher:       newSearchCache(searcher, cfg.cachedir(), cfg.CacheEntries, cacheSize),
+		backends:       backends,
+		missingModules: missingModules,
+		missingSymbols: newMissingCache(symbolTTL),
+		usage:          loadUsage(filepath.Join(cfg.statedir(), "usage.json")),
+		proxy:          modules,
+		sources:        loader,
+		types:          typecheck.New(loader, typecheckPackages),
+		symbols:        newSymbolIndex(sources.Std),
+		api:            api,
+		state:          s,
+	}
+	if cfg.Index != "off" {
+		client := index.New(cfg.Index, http.DefaultClient, userAgent)
+		b.modules = loadModuleIndex(client, filepath.Join(cfg.statedir(), "index.gob"))
 	}
 
 	s.AddHandler(b.OnCommand)
@@ -54,10 +110,52 @@ func run() error {
 	log.Println("Logged in as ", me.Tag())
 
 	go b.gcInteractionData()
+	go b.symbols.build()
 	go b.updateArticles()
+	if b.modules != nil {
+		go b.modules.run()
+	}
+	if interval > 0 {
+		b.prewarm = &prewarmer{searcher: b.searcher, interval: interval}
+		go b.prewarm.run(b.prewarmList())
+	}
 	select {}
 }
 
+// newBackends creates the configured documentation backends, which are
+// searched in order. sources loads the standard library from GOROOT, and
+// modules from the configured module proxies, and loader caches the packages
+// that sources loads.
+func newBackends(cfg configuration, sources gosrc.Split, loader gosrc.Loader) (*fallbackSearcher, error) {
+	names := strings.Split(cfg.Backend, ",")
+	f := &fallbackSearcher{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" && len(names) == 1 {
+			name = "pkgsite"
+		}
+
+		var searcher doc.Searcher
+		switch name {
+		case "pkgsite":
+			searcher = doc.NewSearcher(docsParser{pkgsite.Parser}, doc.UserAgent(userAgent), doc.WithDuplicateTypeFuncs())
+		case "proxy":
+			searcher = srcdoc.New(loader)
+		case "local":
+			var mods gosrc.Chain
+			for _, dir := range cfg.ModDirs {
+				mods = append(mods, gosrc.Dir(dir))
+			}
+			mods = append(mods, gosrc.ModCache(cfg.modcache()))
+			searcher = srcdoc.New(gosrc.Split{Std: sources.Std, Mod: mods})
+		default:
+			return nil, fmt.Errorf("unknown docs backend %q", name)
+		}
+		f.backends = append(f.backends, &backend{name: name, Searcher: searcher})
+	}
+	return f, nil
+}
+
 func main() {
 	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v", err)
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	"github.com/hhhapz/doc"
+)
+
+// missingTTL is how long queries that were not found are remembered, unless
+// configured otherwise.
+const missingTTL = 5 * time.Minute
+
+// missingCache remembers the queries that were not found for ttl, along with
+// the error that they failed with, so that repeated typos are not searched
+// again. A ttl of 0 disables it.
+type missingCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]missingEntry
+}
+
+type missingEntry struct {
+	err     error
+	expires time.Time
+}
+
+func newMissingCache(ttl time.Duration) *missingCache {
+	return &missingCache{
+		ttl:     ttl,
+		entries: map[string]missingEntry{},
+	}
+}
+
+// add remembers that key was not found, and returns err.
+func (m *missingCache) add(key string, err error) error {
+	if m.ttl <= 0 {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = missingEntry{err: err, expires: time.Now().Add(m.ttl)}
+	return err
+}
+
+// get returns the error that key was not found with, or nil if it is not
+// remembered.
+func (m *missingCache) get(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(entry.expires) {
+		delete(m.entries, key)
+		return nil
+	}
+	return entry.err
+}
+
+// keys returns the remembered keys, sorted.
+func (m *missingCache) keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var keys []string
+	for key, entry := range m.entries {
+		if time.Now().Before(entry.expires) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// clear forgets the keys that match, and returns them, sorted. Expired keys
+// are forgotten too, but not returned.
+func (m *missingCache) clear(match func(key string) bool) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var keys []string
+	for key, entry := range m.entries {
+		expired, matched := time.Now().After(entry.expires), match(key)
+		if expired || matched {
+			delete(m.entries, key)
+		}
+		if matched && !expired {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// missingSearcher remembers the modules that a searcher did not find.
+type missingSearcher struct {
+	doc.Searcher
+	missing *missingCache
+}
+
+var _ backendSearcher = (*missingSearcher)(nil)
+
+// Search returns the error that the module was not found with if it is
+// remembered, and searches for it otherwise.
+func (s *missingSearcher) Search(ctx context.Context, module string) (doc.Package, error) {
+	pkg, _, err := s.searchBackend(ctx, module)
+	return pkg, err
+}
+
+// searchBackend is Search, but also returns the name of the backend that found
+// the package, if the wrapped searcher reports it.
+func (s *missingSearcher) searchBackend(ctx context.Context, module string) (doc.Package, string, error) {
+	if err := s.missing.get(module); err != nil {
+		return doc.Package{}, "", err
+	}
+
+	pkg, backend, err := searchBackend(ctx, s.Searcher, module)
+	if isNotFound(err) {
+		s.missing.add(module, err)
+	}
+	return pkg, backend, err
+}
+
+// isNotFound reports whether err means that a package does not exist, rather
+// than that it could not be searched for, such as when a backend is down. The
+// errors of all backends that were searched must be not found errors.
+func isNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			if !isNotFound(err) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var status doc.InvalidStatusError
+	if errors.As(err, &status) {
+		return status == 404
+	}
+	return errors.Is(err, gosrc.ErrNotFound)
+}
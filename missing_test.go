@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	"github.com/hhhapz/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissingCache(t *testing.T) {
+	m := newMissingCache(time.Minute)
+	errTypo := errors.New("not found")
+	assert.Equal(t, errTypo, m.add("gihtub.com/foo", errTypo))
+	m.add("fmt printx", errTypo)
+	m.entries["io"] = missingEntry{err: errTypo, expires: time.Now().Add(-time.Second)}
+
+	assert.Equal(t, errTypo, m.get("gihtub.com/foo"))
+	assert.NoError(t, m.get("io"))
+	assert.Equal(t, []string{"fmt printx", "gihtub.com/foo"}, m.keys())
+
+	cleared := m.clear(func(key string) bool { return strings.HasPrefix(key, "fmt") })
+	assert.Equal(t, []string{"fmt printx"}, cleared)
+	assert.Equal(t, []string{"gihtub.com/foo"}, m.keys())
+
+	m = newMissingCache(0)
+	m.add("gihtub.com/foo", errTypo)
+	assert.NoError(t, m.get("gihtub.com/foo"))
+}
+
+func TestMissingSearcher(t *testing.T) {
+	searches := 0
+	s := &missingSearcher{
+		Searcher: searcherFunc(func(ctx context.Context, module string) (doc.Package, error) {
+			searches++
+			if module == "down.example.com/pkg" {
+				return doc.Package{}, doc.InvalidStatusError(503)
+			}
+			return doc.Package{}, doc.InvalidStatusError(404)
+		}),
+		missing: newMissingCache(time.Minute),
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := s.Search(context.Background(), "gihtub.com/foo")
+		assert.Equal(t, doc.InvalidStatusError(404), err)
+		_, err = s.Search(context.Background(), "down.example.com/pkg")
+		assert.Equal(t, doc.InvalidStatusError(503), err)
+	}
+	assert.Equal(t, 3, searches)
+	assert.Equal(t, []string{"gihtub.com/foo"}, s.missing.keys())
+}
+
+func TestIsNotFound(t *testing.T) {
+	assert.False(t, isNotFound(nil))
+	assert.True(t, isNotFound(doc.InvalidStatusError(404)))
+	assert.True(t, isNotFound(fmt.Errorf("local: %w", gosrc.ErrNotFound)))
+	assert.False(t, isNotFound(context.DeadlineExceeded))
+
+	assert.True(t, isNotFound(errors.Join(
+		fmt.Errorf("pkgsite: %w", doc.InvalidStatusError(404)),
+		fmt.Errorf("proxy: %w", gosrc.ErrNotFound),
+	)))
+	assert.False(t, isNotFound(errors.Join(
+		fmt.Errorf("pkgsite: %w", doc.InvalidStatusError(503)),
+		fmt.Errorf("proxy: %w", gosrc.ErrNotFound),
+	)))
+}
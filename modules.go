@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DiscordGophers/dr-docso/index"
+	"github.com/lithammer/fuzzysearch/fuzzy"
+)
+
+const (
+	// indexInterval is how often new versions are read from the module
+	// index.
+	indexInterval = 10 * time.Minute
+	// indexHistory is how far back a new module index starts reading. All
+	// of it would take hours to read, and a lot of memory to keep.
+	indexHistory = 365 * 24 * time.Hour
+	// indexPageDelay is the time between the pages read from the index, so
+	// that it is not flooded while catching up.
+	indexPageDelay = time.Second
+	// indexSaveEvery is the number of pages read between saves, while
+	// catching up.
+	indexSaveEvery = 100
+	// indexMinQuery is the length of the shortest query that module paths
+	// are suggested for, as shorter ones match most of them.
+	indexMinQuery = 3
+)
+
+// moduleIndex keeps the module paths in a module index, with the number of
+// versions published of each, so that modules can be autocompleted before
+// anyone searched for them. It is saved to a file, so that the index is only
+// read once.
+type moduleIndex struct {
+	client *index.Client
+	file   string
+
+	mu       sync.RWMutex
+	since    time.Time
+	versions map[string]int
+	// paths are the module paths with their lowercase form, so that searches
+	// do not lowercase every path again.
+	paths []indexPath
+}
+
+// indexPath is a module path in the index, with its lowercase form.
+type indexPath struct {
+	path, lower string
+}
+
+// savedIndex is the format of the file that the module index is saved to.
+type savedIndex struct {
+	Since    time.Time
+	Versions map[string]int
+}
+
+// loadModuleIndex loads the module index saved in file. If there is none, it
+// starts reading the index indexHistory ago.
+func loadModuleIndex(client *index.Client, file string) *moduleIndex {
+	idx := &moduleIndex{
+		client:   client,
+		file:     file,
+		since:    time.Now().Add(-indexHistory),
+		versions: map[string]int{},
+	}
+
+	f, err := os.Open(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return idx
+	}
+	if err != nil {
+		log.Printf("Could not load the module index, starting over: %v", err)
+		return idx
+	}
+	defer f.Close()
+
+	var saved savedIndex
+	if err := gob.NewDecoder(f).Decode(&saved); err != nil {
+		log.Printf("Could not load the module index, starting over: %v", err)
+		return idx
+	}
+	idx.since, idx.versions = saved.Since, saved.Versions
+	for path := range idx.versions {
+		idx.paths = append(idx.paths, indexPath{path, strings.ToLower(path)})
+	}
+	return idx
+}
+
+// run reads the new versions in the index every indexInterval.
+func (idx *moduleIndex) run() {
+	for {
+		start := time.Now()
+		if err := idx.sync(context.Background()); err != nil {
+			log.Printf("Could not read the module index: %v", err)
+		}
+		idx.mu.RLock()
+		log.Printf("Read the module index in %s, %d modules", time.Since(start), len(idx.versions))
+		idx.mu.RUnlock()
+		time.Sleep(indexInterval)
+	}
+}
+
+// sync reads the versions published since the index was last read, and saves
+// it.
+func (idx *moduleIndex) sync(ctx context.Context) error {
+	defer idx.save()
+
+	for page := 1; ; page++ {
+		idx.mu.RLock()
+		since := idx.since
+		idx.mu.RUnlock()
+
+		reqCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+		versions, err := idx.client.Versions(reqCtx, since, index.MaxLimit)
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		idx.mu.Lock()
+		for _, v := range versions {
+			// Pages start at the timestamp of the last version of the
+			// previous page, so those versions were counted already.
+			if v.Timestamp.After(idx.since) {
+				if idx.versions[v.Path] == 0 {
+					idx.paths = append(idx.paths, indexPath{v.Path, strings.ToLower(v.Path)})
+				}
+				idx.versions[v.Path]++
+			}
+		}
+		if n := len(versions); n > 0 {
+			last := versions[n-1].Timestamp
+			if !last.After(idx.since) {
+				// A full page of versions with the same timestamp
+				// would be read forever.
+				last = idx.since.Add(time.Nanosecond)
+			}
+			idx.since = last
+		}
+		idx.mu.Unlock()
+
+		if len(versions) < index.MaxLimit {
+			return nil
+		}
+		if page%indexSaveEvery == 0 {
+			idx.save()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(indexPageDelay):
+		}
+	}
+}
+
+// save saves the index to its file.
+func (idx *moduleIndex) save() {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	err := os.MkdirAll(filepath.Dir(idx.file), 0o755)
+	if err != nil {
+		log.Printf("Could not save the module index: %v", err)
+		return
+	}
+	f, err := os.CreateTemp(filepath.Dir(idx.file), ".tmp-*")
+	if err != nil {
+		log.Printf("Could not save the module index: %v", err)
+		return
+	}
+	defer os.Remove(f.Name())
+
+	err = gob.NewEncoder(f).Encode(savedIndex{Since: idx.since, Versions: idx.versions})
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), idx.file)
+	}
+	if err != nil {
+		log.Printf("Could not save the module index: %v", err)
+	}
+}
+
+// search returns up to n module paths that fuzzy match the query, which must
+// be lowercase. Paths whose last element is the query are ranked first, then
+// paths that contain it, and then other fuzzy matches, which are only searched
+// for if there are not enough of the others. Within those, they are ranked by
+// the number of versions they published, as active modules tend to be
+// popular. How often the paths were used is only looked up for the n paths
+// that are returned, and ranks them within their tier.
+func (idx *moduleIndex) search(query string, used func(module string) int, n int) []string {
+	if len(query) < indexMinQuery || n <= 0 {
+		return nil
+	}
+
+	type match struct {
+		path                 string
+		tier, used, versions int
+	}
+	better := func(a, b match) bool {
+		switch {
+		case a.tier != b.tier:
+			return a.tier < b.tier
+		case a.used != b.used:
+			return a.used > b.used
+		case a.versions != b.versions:
+			return a.versions > b.versions
+		case len(a.path) != len(b.path):
+			return len(a.path) < len(b.path)
+		}
+		return a.path < b.path
+	}
+
+	// Only the best n matches are kept, best first, as short queries match
+	// most of the index.
+	best := make([]match, 0, n)
+	add := func(m match) {
+		if len(best) == n && !better(m, best[n-1]) {
+			return
+		}
+		i := sort.Search(len(best), func(i int) bool {
+			return better(m, best[i])
+		})
+		if len(best) < n {
+			best = append(best, match{})
+		}
+		copy(best[i+1:], best[i:len(best)-1])
+		best[i] = m
+	}
+
+	idx.mu.RLock()
+	for _, p := range idx.paths {
+		switch {
+		case p.lower == query || strings.HasSuffix(p.lower, "/"+query):
+			add(match{path: p.path, tier: 0, versions: idx.versions[p.path]})
+		case strings.Contains(p.lower, query):
+			add(match{path: p.path, tier: 1, versions: idx.versions[p.path]})
+		}
+	}
+	if len(best) < n {
+		for _, p := range idx.paths {
+			if !strings.Contains(p.lower, query) && fuzzy.Match(query, p.lower) {
+				add(match{path: p.path, tier: 2, versions: idx.versions[p.path]})
+			}
+		}
+	}
+	idx.mu.RUnlock()
+
+	for i := range best {
+		best[i].used = used(best[i].path)
+	}
+	sort.SliceStable(best, func(i, j int) bool {
+		return better(best[i], best[j])
+	})
+
+	paths := make([]string, 0, len(best))
+	for _, m := range best {
+		paths = append(paths, m.path)
+	}
+	return paths
+}
+
+// indexRanks returns the ranks of the modules in the index that match the
+// query, and are not in packages, the module paths that are already ranked.
+// They are ranked after the ranks that are already found, in order of
+// popularity.
+func (b *botState) indexRanks(query string, packages map[string]string, ranks fuzzy.Ranks) fuzzy.Ranks {
+	if b.modules == nil {
+		return ranks
+	}
+
+	var distance int
+	for _, r := range ranks {
+		distance = max(distance, r.Distance+1)
+	}
+	for _, mod := range b.modules.search(strings.ToLower(query), b.usage.count, 25) {
+		if _, ok := packages[mod]; ok {
+			continue
+		}
+		ranks = append(ranks, fuzzy.Rank{
+			Source:        query,
+			Target:        mod,
+			Distance:      distance,
+			OriginalIndex: -1,
+		})
+		distance++
+	}
+	return ranks
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DiscordGophers/dr-docso/index"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleIndex(t *testing.T) {
+	feed := []index.Version{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.0", Timestamp: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Path: "github.com/gin-gonic/gin", Version: "v1.9.1", Timestamp: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{Path: "github.com/gin-contrib/cors", Version: "v1.4.0", Timestamp: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{Path: "github.com/go-ini/ini", Version: "v1.67.0", Timestamp: time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{Path: "github.com/example/engine", Version: "v0.1.0", Timestamp: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		since, _ := time.Parse(time.RFC3339Nano, r.URL.Query().Get("since"))
+		for _, v := range feed {
+			if !v.Timestamp.Before(since) {
+				w.Write([]byte(`{"Path":"` + v.Path + `","Version":"` + v.Version + `","Timestamp":"` + v.Timestamp.Format(time.RFC3339Nano) + `"}` + "\n"))
+			}
+		}
+	}))
+	defer srv.Close()
+
+	file := filepath.Join(t.TempDir(), "index.gob")
+	idx := loadModuleIndex(index.New(srv.URL, nil, ""), file)
+	idx.since = time.Time{}
+	require.NoError(t, idx.sync(context.Background()))
+	assert.Equal(t, map[string]int{
+		"github.com/gin-gonic/gin":    2,
+		"github.com/gin-contrib/cors": 1,
+		"github.com/go-ini/ini":       1,
+		"github.com/example/engine":   1,
+	}, idx.versions)
+
+	// Versions at the timestamp that the index was read up to are not
+	// counted twice.
+	require.NoError(t, idx.sync(context.Background()))
+	assert.Equal(t, 2, idx.versions["github.com/gin-gonic/gin"])
+
+	used := func(module string) int {
+		if module == "github.com/gin-contrib/cors" {
+			return 3
+		}
+		return 0
+	}
+	assert.Equal(t, []string{
+		"github.com/gin-gonic/gin",
+		"github.com/gin-contrib/cors",
+		"github.com/example/engine",
+		"github.com/go-ini/ini",
+	}, idx.search("gin", used, 5))
+	assert.Empty(t, idx.search("gi", used, 5))
+
+	// Fuzzy matches are not searched for if there are enough others, and
+	// usage only ranks the paths that are returned.
+	assert.Equal(t, []string{
+		"github.com/gin-gonic/gin",
+		"github.com/example/engine",
+	}, idx.search("gin", used, 2))
+
+	saved := loadModuleIndex(index.New(srv.URL, nil, ""), file)
+	assert.Equal(t, idx.versions, saved.versions)
+	assert.ElementsMatch(t, idx.paths, saved.paths)
+	assert.True(t, idx.since.Equal(saved.since))
+}
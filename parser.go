@@ -0,0 +1,86 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/hhhapz/doc"
+)
+
+// docsParser wraps pkgsite.Parser, adding the package examples that it does
+// not parse.
+type docsParser struct {
+	doc.Parser
+}
+
+func (p docsParser) Parse(document *goquery.Document, useCase, dupeTypeFuncs bool) (doc.Package, error) {
+	pkg, err := p.Parser.Parse(document, useCase, dupeTypeFuncs)
+	if err != nil {
+		return pkg, err
+	}
+
+	pkg.Examples = parseExamples(document)
+	for _, ex := range pkg.Examples {
+		attachExample(&pkg, ex, useCase)
+	}
+	return pkg, nil
+}
+
+// parseExamples parses all examples on the page. The name of an example is its
+// anchor without the "example-" prefix, for example "Builder",
+// "Buffer.Grow", "FileServer-StripPrefix" or "package-Basic".
+func parseExamples(document *goquery.Document) []doc.Example {
+	var examples []doc.Example
+	document.Find("details.Documentation-exampleDetails").Each(func(i int, sel *goquery.Selection) {
+		name := strings.TrimPrefix(sel.AttrOr("id", ""), "example-")
+		if name == "" {
+			return
+		}
+
+		code := sel.Find(".Documentation-exampleCode").First().Text()
+		output := sel.Find(".Documentation-exampleOutput").First().Text()
+		examples = append(examples, doc.Example{
+			Name:   name,
+			Code:   strings.TrimSpace(code),
+			Output: strings.TrimSpace(output),
+		})
+	})
+	return examples
+}
+
+// attachExample adds the example to the function, type or method it
+// documents.
+func attachExample(pkg *doc.Package, ex doc.Example, useCase bool) {
+	target, _, _ := strings.Cut(ex.Name, "-")
+	if !useCase {
+		target = strings.ToLower(target)
+	}
+
+	typName, methodName, isMethod := strings.Cut(target, ".")
+	if isMethod {
+		typ, ok := pkg.Types[typName]
+		if !ok {
+			return
+		}
+		if method, ok := typ.Methods[methodName]; ok {
+			method.Examples = append(method.Examples, ex)
+			typ.Methods[methodName] = method
+		}
+		return
+	}
+
+	if typ, ok := pkg.Types[target]; ok {
+		typ.Examples = append(typ.Examples, ex)
+		pkg.Types[target] = typ
+	}
+	if fn, ok := pkg.Functions[target]; ok {
+		fn.Examples = append(fn.Examples, ex)
+		pkg.Functions[target] = fn
+	}
+	for _, typ := range pkg.Types {
+		if fn, ok := typ.TypeFunctions[target]; ok {
+			fn.Examples = append(fn.Examples, ex)
+			typ.TypeFunctions[target] = fn
+		}
+	}
+}
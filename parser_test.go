@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/hhhapz/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+const examplesHTML = `
+<div class="Documentation-content">
+<details tabindex="-1" id="example-package-Basic" class="Documentation-exampleDetails js-exampleContainer">
+	<summary class="Documentation-exampleDetailsHeader">Example (Basic)</summary>
+	<div class="Documentation-exampleDetailsBody">
+		<pre class="Documentation-exampleCode">package main</pre>
+	</div>
+</details>
+<details tabindex="-1" id="example-Builder" class="Documentation-exampleDetails js-exampleContainer">
+	<summary class="Documentation-exampleDetailsHeader">Example</summary>
+	<div class="Documentation-exampleDetailsBody">
+		<textarea class="Documentation-exampleCode code" spellcheck="false">
+package main
+
+func main() {}
+</textarea>
+		<pre><span class="Documentation-exampleOutputLabel">Output:</span>
+<span class="Documentation-exampleOutput">3...2...1...ignition</span></pre>
+	</div>
+</details>
+<details tabindex="-1" id="example-Builder.Grow" class="Documentation-exampleDetails js-exampleContainer">
+	<textarea class="Documentation-exampleCode code">grow</textarea>
+</details>
+<details tabindex="-1" id="example-NewReplacer-Unicode" class="Documentation-exampleDetails js-exampleContainer">
+	<textarea class="Documentation-exampleCode code">replace</textarea>
+</details>
+</div>`
+
+func TestParseExamples(t *testing.T) {
+	document, err := goquery.NewDocumentFromReader(strings.NewReader(examplesHTML))
+	assert.NoError(t, err)
+
+	examples := parseExamples(document)
+	assert.Equal(t, []doc.Example{
+		{Name: "package-Basic", Code: "package main"},
+		{Name: "Builder", Code: "package main\n\nfunc main() {}", Output: "3...2...1...ignition"},
+		{Name: "Builder.Grow", Code: "grow"},
+		{Name: "NewReplacer-Unicode", Code: "replace"},
+	}, examples)
+
+	pkg := doc.Package{
+		Functions: map[string]doc.Function{
+			"newreplacer": {Name: "NewReplacer"},
+		},
+		Types: map[string]doc.Type{
+			"builder": {
+				Name: "Builder",
+				Methods: map[string]doc.Method{
+					"grow": {For: "Builder", Function: doc.Function{Name: "Grow"}},
+				},
+			},
+		},
+	}
+	for _, ex := range examples {
+		attachExample(&pkg, ex, false)
+	}
+
+	assert.Equal(t, []doc.Example{examples[1]}, pkg.Types["builder"].Examples)
+	assert.Equal(t, []doc.Example{examples[2]}, pkg.Types["builder"].Methods["grow"].Examples)
+	assert.Equal(t, []doc.Example{examples[3]}, pkg.Functions["newreplacer"].Examples)
+}
+
+func TestExamplePages(t *testing.T) {
+	short := examplePages(doc.Example{Code: "fmt.Println(1)", Output: "1"})
+	assert.Equal(t, []string{"```go\nfmt.Println(1)\n```\n**Output:**```\n1```"}, short)
+
+	line := strings.Repeat("x", 99)
+	long := examplePages(doc.Example{Code: strings.Repeat(line+"\n", 100)})
+	assert.Len(t, long, 3)
+	for _, page := range long {
+		assert.True(t, strings.HasPrefix(page, "```go\n"))
+		assert.True(t, strings.HasSuffix(page, "\n```"))
+		assert.LessOrEqual(t, len(page), examplePageLimit+len("```go\n```"))
+	}
+
+	// Long output is cut between runes.
+	output := examplePages(doc.Example{Code: "fmt.Println()", Output: strings.Repeat("é", examplePageLimit)})
+	last := output[len(output)-1]
+	assert.True(t, utf8.ValidString(last))
+	assert.True(t, strings.HasSuffix(last, "...```"))
+	assert.LessOrEqual(t, len(last), examplePageLimit)
+}
+
+func TestExamplesSelect(t *testing.T) {
+	examples := make([]doc.Example, 30)
+	for i := range examples {
+		examples[i].Name = fmt.Sprintf("Example%d", i)
+	}
+
+	sel := examplesSelect("id", examples, 25, 25/browseLimit)
+	assert.Equal(t, "Display Example (2/2)", sel.Placeholder)
+	assert.Equal(t, browsePage+"0", sel.Options[0].Value)
+	assert.Equal(t, "23", sel.Options[1].Value)
+	assert.True(t, sel.Options[3].Default)
+}
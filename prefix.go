@@ -0,0 +1,155 @@
+package main
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+)
+
+// prefixCommands are the commands that can be used as text commands, with
+// the configured prefix.
+var prefixCommands = map[string]bool{
+	"docs": true,
+	"spec": true,
+	"blog": true,
+	"info": true,
+}
+
+// parsePrefixCommand parses a text command such as "dr.docs http Client.Do"
+// into its name and arguments. The prefix and name are case insensitive, as
+// phone keyboards tend to capitalize them.
+func parsePrefixCommand(content, prefix string) (name, args string, ok bool) {
+	content = strings.TrimSpace(content)
+	if prefix == "" || len(content) < len(prefix) || !strings.EqualFold(content[:len(prefix)], prefix) {
+		return "", "", false
+	}
+
+	fields := strings.Fields(content[len(prefix):])
+	if len(fields) == 0 {
+		return "", "", false
+	}
+	name = strings.ToLower(fields[0])
+	if !prefixCommands[name] {
+		return "", "", false
+	}
+	return name, strings.Join(fields[1:], " "), true
+}
+
+// handlePrefix handles a text command, with the same handlers as the slash
+// command of the same name.
+func (b *botState) handlePrefix(m *gateway.MessageCreateEvent, name, args string) {
+	var data *api.InteractionResponseData
+	switch name {
+	case "docs":
+		if args == "" {
+			args = "help"
+		}
+		b.handleDocsText(m, []textQuery{{args, "prefix"}})
+		return
+	case "spec":
+		log.Printf("%s used spec(%q) text version", m.Author.Tag(), args)
+		data = specResponse(args)
+	case "blog":
+		log.Printf("%s used blog(%q) text version", m.Author.Tag(), args)
+		data = b.blogResponse(args, true)
+	case "info":
+		log.Printf("%s used info text version", m.Author.Tag())
+		data = b.infoResponse()
+	}
+
+	var embeds []discord.Embed
+	if data.Embeds != nil {
+		embeds = *data.Embeds
+	}
+	components := discord.ContainerComponents{}
+	if data.Components != nil {
+		components = *data.Components
+	}
+	b.replyText(m, args, embeds, components)
+}
+
+// checkTextOwner reports whether the user of a component may use it. The
+// replies to text commands are public rather than ephemeral, so only the
+// sender of the command may use their components, while it has not expired.
+// Otherwise, the user is told why and false is returned.
+func (b *botState) checkTextOwner(e *gateway.InteractionCreateEvent) bool {
+	if e.Message == nil || e.Message.Flags&discord.EphemeralMessage != 0 {
+		return true
+	}
+
+	mu.Lock()
+	var owner discord.UserID
+	for _, data := range interactionMap {
+		if data.token == "" && data.messageID == e.Message.ID {
+			owner = data.userID
+			break
+		}
+	}
+	mu.Unlock()
+
+	switch {
+	case !owner.IsValid():
+		b.respondError(e, expired)
+		return false
+	case e.User.ID != owner:
+		b.respondError(e, notOwner)
+		return false
+	}
+	return true
+}
+
+// replyText replies to a text command or query. If the message was already
+// replied to, and is edited, the reply is edited instead. Edits made while the
+// reply is still being sent are dropped, as there is no reply to edit yet.
+func (b *botState) replyText(m *gateway.MessageCreateEvent, query string, embeds []discord.Embed, components discord.ContainerComponents) {
+	mu.Lock()
+	data, ok := interactionMap[m.ID.String()]
+	var channelID discord.ChannelID
+	var messageID discord.MessageID
+	if ok {
+		data.query = query
+		channelID, messageID = data.channelID, data.messageID
+	}
+	mu.Unlock()
+
+	if ok {
+		if messageID.IsValid() {
+			b.state.EditMessageComplex(channelID, messageID, api.EditMessageData{
+				Embeds:     &embeds,
+				Components: &components,
+			})
+		}
+		return
+	}
+
+	mu.Lock()
+	interactionMap[m.ID.String()] = &interactionData{
+		id:      m.ID.String(),
+		created: time.Now(),
+		userID:  m.Author.ID,
+		query:   query,
+	}
+	mu.Unlock()
+
+	msg, err := b.state.SendMessageComplex(m.ChannelID, api.SendMessageData{
+		Components: components,
+		Embeds:     embeds,
+	})
+	if err != nil {
+		mu.Lock()
+		delete(interactionMap, m.ID.String())
+		mu.Unlock()
+		return
+	}
+
+	mu.Lock()
+	if data, ok := interactionMap[m.ID.String()]; ok {
+		data.channelID = msg.ChannelID
+		data.messageID = msg.ID
+	}
+	mu.Unlock()
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePrefixCommand(t *testing.T) {
+	tests := []struct {
+		content, prefix string
+		name, args      string
+		ok              bool
+	}{
+		{"dr.docs http Client.Do", "dr.", "docs", "http Client.Do", true},
+		{"  Dr.Docs   http  Client.Do ", "dr.", "docs", "http Client.Do", true},
+		{"dr.spec composite literals", "dr.", "spec", "composite literals", true},
+		{"dr.info", "dr.", "info", "", true},
+		{"!blog generics", "!", "blog", "generics", true},
+		{"dr. docs fmt", "dr.", "docs", "fmt", true},
+		{"dr.config", "dr.", "", "", false},
+		{"dr.", "dr.", "", "", false},
+		{"d.docs fmt", "dr.", "", "", false},
+		{"docs fmt", "", "", "", false},
+	}
+	for _, tt := range tests {
+		name, args, ok := parsePrefixCommand(tt.content, tt.prefix)
+		assert.Equal(t, tt.ok, ok, tt.content)
+		assert.Equal(t, tt.name, name, tt.content)
+		assert.Equal(t, tt.args, args, tt.content)
+	}
+}
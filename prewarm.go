@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hhhapz/doc"
+)
+
+const (
+	// prewarmInterval is the time between the searches of prewarmed
+	// packages, unless configured otherwise, so that upstream is not
+	// flooded after a restart.
+	prewarmInterval = 500 * time.Millisecond
+	// prewarmModules is the number of most used modules that are prewarmed,
+	// besides the standard library.
+	prewarmModules = 20
+)
+
+// prewarmer searches packages in the background after startup, so that they
+// are cached before they are first used, and autocomplete can suggest their
+// symbols right away.
+type prewarmer struct {
+	searcher *searchCache
+	interval time.Duration
+
+	mu     sync.Mutex
+	total  int
+	done   int
+	failed []string
+}
+
+// run searches the modules, one every interval. It stops early once the cache
+// is full, so that the packages that are used are not evicted.
+func (p *prewarmer) run(modules []string) {
+	p.mu.Lock()
+	p.total = len(modules)
+	p.mu.Unlock()
+
+	start := time.Now()
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for _, module := range modules {
+		if p.searcher.full() {
+			log.Printf("Stopped prewarming, the cache is full")
+			break
+		}
+
+		var cached bool
+		p.searcher.WithCache(func(cache map[string]*doc.CachedPackage) {
+			_, cached = cache[module]
+		})
+		if !cached {
+			<-ticker.C
+			ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+			_, err := p.searcher.Search(ctx, module)
+			cancel()
+			if err != nil {
+				log.Printf("Could not prewarm %q: %v", module, err)
+				p.mu.Lock()
+				p.failed = append(p.failed, module)
+				p.mu.Unlock()
+			}
+		}
+
+		p.mu.Lock()
+		p.done++
+		p.mu.Unlock()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	log.Printf("Prewarmed %d packages in %s, %d failed", p.done, time.Since(start), len(p.failed))
+}
+
+// status describes the progress of prewarming, and the packages that failed.
+func (p *prewarmer) status() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := fmt.Sprintf("%d / %d packages, %d failed", p.done, p.total, len(p.failed))
+	if len(p.failed) > 0 {
+		failed := p.failed[:min(len(p.failed), 5)]
+		status += fmt.Sprintf(" (%s", strings.Join(failed, ", "))
+		if len(p.failed) > len(failed) {
+			status += fmt.Sprintf(", and %d more", len(p.failed)-len(failed))
+		}
+		status += ")"
+	}
+	return status
+}
+
+// prewarmList returns the modules to prewarm: the most used modules, followed
+// by the rest of the standard library.
+func (b *botState) prewarmList() []string {
+	seen := map[string]bool{}
+	modules := b.usage.top(prewarmModules)
+	for _, module := range modules {
+		seen[module] = true
+	}
+
+	var std []string
+	for lib := range stdlib {
+		// Unlike the other skipped packages, builtin and unsafe are
+		// documented.
+		if seen[lib] || skipStdlib(lib) && lib != "builtin" && lib != "unsafe" {
+			continue
+		}
+		std = append(std, lib)
+	}
+	sort.Strings(std)
+	return append(modules, std...)
+}
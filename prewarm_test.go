@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hhhapz/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrewarmer(t *testing.T) {
+	var searched []string
+	searcher := searcherFunc(func(ctx context.Context, module string) (doc.Package, error) {
+		searched = append(searched, module)
+		if module == "archive" {
+			return doc.Package{}, errors.New("not a package")
+		}
+		return doc.Package{URL: module}, nil
+	})
+
+	c := newSearchCache(searcher, "", 3, 0)
+	_, err := c.Search(context.Background(), "fmt")
+	assert.NoError(t, err)
+
+	p := &prewarmer{searcher: c, interval: time.Millisecond}
+	p.run([]string{"fmt", "archive", "io", "os", "strings"})
+
+	// fmt was cached already, and prewarming stops once the cache is full.
+	assert.Equal(t, []string{"fmt", "archive", "io", "os"}, searched)
+	assert.Equal(t, "4 / 5 packages, 1 failed (archive)", p.status())
+}
+
+func TestUsage(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cache", "usage.json")
+	u := loadUsage(file)
+	for _, module := range []string{"net/http", "github.com/hhhapz/doc", "net/http", "fmt", "net/http", "fmt"} {
+		u.record(module)
+	}
+	assert.Equal(t, []string{"net/http", "fmt"}, u.top(2))
+	assert.NoError(t, u.save())
+
+	u = loadUsage(file)
+	assert.Equal(t, []string{"net/http", "fmt", "github.com/hhhapz/doc"}, u.top(5))
+}
@@ -0,0 +1,318 @@
+// Package proxy implements a client for the Go module proxy protocol, as
+// described in https://go.dev/ref/mod#goproxy-protocol.
+//
+// Besides http and https proxies, file:// proxies are supported, which read
+// the same directory layout from the local file system.
+package proxy
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// DefaultURL is the proxy used when none is configured.
+const DefaultURL = "https://proxy.golang.org"
+
+// maxZipSize is the largest module zip that will be downloaded.
+const maxZipSize = 100 << 20
+
+// ErrNotFound is returned when none of the proxies know the requested module
+// or version.
+var ErrNotFound = errors.New("module not found")
+
+// StatusError is returned when a proxy responds with an unexpected status.
+type StatusError struct {
+	URL    string
+	Status int
+}
+
+func (err StatusError) Error() string {
+	return fmt.Sprintf("%s: invalid response status: %d", err.URL, err.Status)
+}
+
+// Info is the metadata of a module version.
+type Info struct {
+	Version string
+	Time    time.Time
+}
+
+// Client queries a list of module proxies. Like the go command, a proxy is
+// only skipped if it does not know the module, any other error is returned
+// right away.
+type Client struct {
+	proxies []string
+	client  *http.Client
+	agent   string
+}
+
+// New creates a client from a proxy list in the GOPROXY format, for example
+// "https://athens.example.com,https://proxy.golang.org". The "direct" and
+// "off" keywords are ignored. If the list is empty, DefaultURL is used.
+func New(proxies string, client *http.Client, agent string) *Client {
+	c := &Client{client: client, agent: agent}
+	for _, p := range strings.FieldsFunc(proxies, func(r rune) bool { return r == ',' || r == '|' }) {
+		p = strings.TrimSpace(p)
+		if p == "" || p == "direct" || p == "off" {
+			continue
+		}
+		c.proxies = append(c.proxies, strings.TrimSuffix(p, "/"))
+	}
+	if len(c.proxies) == 0 {
+		c.proxies = []string{DefaultURL}
+	}
+	if c.client == nil {
+		c.client = http.DefaultClient
+	}
+	return c
+}
+
+// Proxies returns the proxies that are queried, in order.
+func (c *Client) Proxies() []string {
+	return c.proxies
+}
+
+// List returns the published versions of the module, in the order returned
+// by the proxy.
+func (c *Client) List(ctx context.Context, mod string) ([]string, error) {
+	escaped, err := module.EscapePath(mod)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.get(ctx, escaped+"/@v/list", 0)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(body)), nil
+}
+
+// Latest returns the latest version of the module. Like the go command, if
+// the proxies do not serve @latest, the latest version in the list of
+// published versions is used.
+func (c *Client) Latest(ctx context.Context, mod string) (Info, error) {
+	escaped, err := module.EscapePath(mod)
+	if err != nil {
+		return Info{}, err
+	}
+
+	info, err := c.info(ctx, escaped+"/@latest")
+	if !errors.Is(err, ErrNotFound) {
+		return info, err
+	}
+
+	versions, err := c.List(ctx, mod)
+	if err != nil {
+		return Info{}, err
+	}
+	latest := Latest(versions)
+	if latest == "" {
+		return Info{}, ErrNotFound
+	}
+	return c.Info(ctx, mod, latest)
+}
+
+// Latest returns the latest release in the versions, or the latest
+// pre-release if there are no releases. Invalid versions are ignored.
+func Latest(versions []string) string {
+	var release, prerelease string
+	for _, v := range versions {
+		switch {
+		case !semver.IsValid(v):
+		case semver.Prerelease(v) != "":
+			if semver.Compare(v, prerelease) > 0 {
+				prerelease = v
+			}
+		case semver.Compare(v, release) > 0:
+			release = v
+		}
+	}
+	if release != "" {
+		return release
+	}
+	return prerelease
+}
+
+// Info returns the metadata of a version of the module.
+func (c *Client) Info(ctx context.Context, mod, version string) (Info, error) {
+	escaped, err := module.EscapeVersion(version)
+	if err != nil {
+		return Info{}, err
+	}
+	escapedMod, err := module.EscapePath(mod)
+	if err != nil {
+		return Info{}, err
+	}
+	return c.info(ctx, escapedMod+"/@v/"+escaped+".info")
+}
+
+func (c *Client) info(ctx context.Context, p string) (Info, error) {
+	body, err := c.get(ctx, p, 0)
+	if err != nil {
+		return Info{}, err
+	}
+
+	var info Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		return Info{}, fmt.Errorf("could not parse %s: %w", p, err)
+	}
+	return info, nil
+}
+
+// Zip downloads the zip of a module version. All files in the zip are
+// prefixed with "<module>@<version>/".
+func (c *Client) Zip(ctx context.Context, mod, version string) (*zip.Reader, error) {
+	escaped, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	escapedMod, err := module.EscapePath(mod)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.get(ctx, escapedMod+"/@v/"+escaped+".zip", maxZipSize)
+	if err != nil {
+		return nil, err
+	}
+	return zip.NewReader(bytes.NewReader(body), int64(len(body)))
+}
+
+// Module finds the module that provides the package with the import path.
+// Like the go command, the longest module path is preferred. If version is
+// empty, the latest version is used.
+func (c *Client) Module(ctx context.Context, pkg, version string) (string, Info, error) {
+	for mod := pkg; strings.Contains(mod, "/"); mod = path.Dir(mod) {
+		var info Info
+		var err error
+		if version == "" {
+			info, err = c.Latest(ctx, mod)
+		} else {
+			info, err = c.Info(ctx, mod, version)
+		}
+
+		switch {
+		case err == nil:
+			return mod, info, nil
+		case !errors.Is(err, ErrNotFound):
+			return "", Info{}, err
+		}
+	}
+	return "", Info{}, ErrNotFound
+}
+
+// Versions finds the module that provides the package with the import path,
+// like Module, and returns its published versions, newest first. Invalid
+// versions are left out.
+func (c *Client) Versions(ctx context.Context, pkg string) (string, []string, error) {
+	for mod := pkg; strings.Contains(mod, "/"); mod = path.Dir(mod) {
+		list, err := c.List(ctx, mod)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			continue
+		case err != nil:
+			return "", nil, err
+		}
+
+		versions := make([]string, 0, len(list))
+		for _, v := range list {
+			if semver.IsValid(v) {
+				versions = append(versions, v)
+			}
+		}
+		if len(versions) == 0 {
+			continue
+		}
+		semver.Sort(versions)
+		slices.Reverse(versions)
+		return mod, versions, nil
+	}
+	return "", nil, ErrNotFound
+}
+
+// get requests the path from every proxy in order, until one of them knows
+// it. If limit is not zero, responses larger than limit are rejected.
+func (c *Client) get(ctx context.Context, p string, limit int64) ([]byte, error) {
+	for _, proxy := range c.proxies {
+		var body []byte
+		var err error
+		if root, ok := strings.CutPrefix(proxy, "file://"); ok {
+			body, err = c.readFile(root, p, limit)
+		} else {
+			body, err = c.request(ctx, proxy+"/"+p, limit)
+		}
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		return body, err
+	}
+	return nil, ErrNotFound
+}
+
+func (c *Client) readFile(root, p string, limit int64) ([]byte, error) {
+	f, err := os.Open(filepath.Join(filepath.FromSlash(root), filepath.FromSlash(p)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readAll(f, limit)
+}
+
+func (c *Client) request(ctx context.Context, u string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	if c.agent != "" {
+		req.Header.Set("User-Agent", c.agent)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return readAll(resp.Body, limit)
+	case http.StatusNotFound, http.StatusGone:
+		return nil, ErrNotFound
+	default:
+		return nil, StatusError{URL: u, Status: resp.StatusCode}
+	}
+}
+
+func readAll(r io.Reader, limit int64) ([]byte, error) {
+	if limit == 0 {
+		return io.ReadAll(r)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("response is larger than %d bytes", limit)
+	}
+	return body, nil
+}
@@ -0,0 +1,109 @@
+package proxy
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeProxy creates a file based proxy serving a single module version.
+func writeProxy(t *testing.T, mod, version string, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	v := filepath.Join(dir, filepath.FromSlash(mod), "@v")
+	require.NoError(t, os.MkdirAll(v, 0o755))
+
+	info := `{"Version":"` + version + `","Time":"2021-01-01T00:00:00Z"}`
+	require.NoError(t, os.WriteFile(filepath.Join(v, "list"), []byte(version+"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(v, version+".info"), []byte(info), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, filepath.FromSlash(mod), "@latest"), []byte(info), 0o644))
+
+	f, err := os.Create(filepath.Join(v, version+".zip"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(mod + "@" + version + "/" + name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return dir
+}
+
+func TestClient(t *testing.T) {
+	dir := writeProxy(t, "example.com/mod", "v1.2.3", map[string]string{
+		"go.mod":     "module example.com/mod\n",
+		"sub/sub.go": "package sub\n",
+	})
+
+	// The first proxy does not know the module, so the second is used.
+	c := New("file://"+t.TempDir()+",file://"+dir+",direct", nil, "")
+	assert.Len(t, c.Proxies(), 2)
+	ctx := context.Background()
+
+	versions, err := c.List(ctx, "example.com/mod")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v1.2.3"}, versions)
+
+	info, err := c.Latest(ctx, "example.com/mod")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.2.3", info.Version)
+
+	mod, info, err := c.Module(ctx, "example.com/mod/sub", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/mod", mod)
+	assert.Equal(t, "v1.2.3", info.Version)
+
+	_, _, err = c.Module(ctx, "example.com/mod/sub", "v0.0.1")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	zr, err := c.Zip(ctx, "example.com/mod", "v1.2.3")
+	assert.NoError(t, err)
+	assert.Len(t, zr.File, 2)
+
+	_, err = c.Latest(ctx, "example.com/missing")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestLatestFromList(t *testing.T) {
+	dir := writeProxy(t, "example.com/mod", "v1.2.3", map[string]string{"go.mod": "module example.com/mod\n"})
+	require.NoError(t, os.Remove(filepath.Join(dir, "example.com", "mod", "@latest")))
+
+	info, err := New("file://"+dir, nil, "").Latest(context.Background(), "example.com/mod")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.2.3", info.Version)
+
+	assert.Equal(t, "v1.10.0", Latest([]string{"v1.9.0", "v1.10.0", "v2.0.0-rc.1", "bad"}))
+	assert.Equal(t, "v2.0.0-rc.2", Latest([]string{"v2.0.0-rc.1", "v2.0.0-rc.2"}))
+	assert.Equal(t, "", Latest(nil))
+}
+
+func TestVersions(t *testing.T) {
+	dir := writeProxy(t, "example.com/mod", "v1.2.3", map[string]string{"go.mod": "module example.com/mod\n"})
+	list := "v1.2.3\nv1.10.0\nv1.11.0-rc.1\nbad\nv0.9.0\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "example.com", "mod", "@v", "list"), []byte(list), 0o644))
+
+	c := New("file://"+dir, nil, "")
+	mod, versions, err := c.Versions(context.Background(), "example.com/mod/sub/pkg")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/mod", mod)
+	assert.Equal(t, []string{"v1.11.0-rc.1", "v1.10.0", "v1.2.3", "v0.9.0"}, versions)
+
+	_, _, err = c.Versions(context.Background(), "example.com/missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestNewDefault(t *testing.T) {
+	assert.Equal(t, []string{DefaultURL}, New("", nil, "").Proxies())
+	assert.Equal(t, []string{DefaultURL}, New("direct", nil, "").Proxies())
+}
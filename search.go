@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+const (
+	searchResults = 10
+	snippetBefore = 60
+	snippetAfter  = 140
+
+	searchUsage = "Usage: `/docs module:search text:<text> [stdlib:true]`"
+)
+
+// searchHit is a symbol whose doc comment matches a full-text search.
+type searchHit struct {
+	sym     indexedSymbol
+	score   int
+	snippet string
+}
+
+// searchDocs searches the doc comments of the symbols for the text. All words
+// of the text must appear in a comment for it to match. Comments containing
+// the whole phrase are ranked highest, followed by the number of matched
+// words, and symbols named after a word.
+func searchDocs(text string, symbols []indexedSymbol) []searchHit {
+	phrase := strings.Join(strings.Fields(strings.ToLower(strings.Trim(text, `"' `))), " ")
+	terms := strings.Fields(phrase)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var hits []searchHit
+	for _, sym := range symbols {
+		if sym.doc == "" {
+			continue
+		}
+		doc := strings.Join(strings.Fields(sym.doc), " ")
+		lower := strings.ToLower(doc)
+		if len(lower) != len(doc) {
+			// Offsets in the lowered text must match the original.
+			doc = lower
+		}
+
+		score := 0
+		for _, term := range terms {
+			n := strings.Count(lower, term)
+			if n == 0 {
+				score = 0
+				break
+			}
+			score += min(n, 5)
+			if strings.Contains(strings.ToLower(sym.name), term) {
+				score += 5
+			}
+		}
+		if score == 0 {
+			continue
+		}
+
+		at := strings.Index(lower, phrase)
+		if at != -1 {
+			score += 20
+		} else {
+			at = strings.Index(lower, terms[0])
+		}
+
+		hits = append(hits, searchHit{
+			sym:     sym,
+			score:   score,
+			snippet: snippet(doc, lower, at, terms),
+		})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].sym.query() < hits[j].sym.query()
+	})
+	return hits
+}
+
+// snippet returns the text around the match at the offset, with all terms in
+// bold. lower is the lowercased text, which is used to find the terms.
+func snippet(text, lower string, at int, terms []string) string {
+	start, end := max(at-snippetBefore, 0), min(at+snippetAfter, len(text))
+	if start > 0 {
+		if i := strings.IndexByte(text[start:at], ' '); i != -1 {
+			start += i + 1
+		}
+	}
+	if end < len(text) {
+		if i := strings.LastIndexByte(text[at:end], ' '); i > 0 {
+			end = at + i
+		}
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("...")
+	}
+	for i := start; i < end; {
+		next, length := end, 0
+		for _, term := range terms {
+			if j := strings.Index(lower[i:end], term); j != -1 && i+j < next {
+				next, length = i+j, len(term)
+			}
+		}
+
+		sb.WriteString(escapeMarkdown(text[i:next]))
+		if length == 0 {
+			break
+		}
+		sb.WriteString("**" + escapeMarkdown(text[next:next+length]) + "**")
+		i = next + length
+	}
+	if end < len(text) {
+		sb.WriteString("...")
+	}
+	return sb.String()
+}
+
+// escapeMarkdown escapes the characters that Discord uses for formatting.
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("*", `\*`, "_", `\_`, "`", "\\`", "~", `\~`, "|", `\|`).Replace(s)
+}
+
+// search searches the doc comments of the cached packages, and optionally of
+// the whole standard library, which is waited for until ctx is done if it is
+// not indexed yet. The hits can be opened with a select menu.
+func (b *botState) search(ctx context.Context, user discord.User, id, text string, std bool) (discord.Embed, discord.ContainerComponents) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return failEmbed("Error", searchUsage), nil
+	}
+	log.Printf("%s used docs search(%q, stdlib: %t)", user.Tag(), text, std)
+
+	var symbols []indexedSymbol
+	if std {
+		b.symbols.wait(ctx)
+		symbols = b.symbols.stdlib()
+	}
+	symbols = b.indexedSymbols(symbols)
+
+	packages := map[string]bool{}
+	for _, sym := range symbols {
+		packages[sym.pkg] = true
+	}
+
+	hits := searchDocs(text, symbols)
+	if len(hits) == 0 {
+		msg := fmt.Sprintf("No documentation in %d packages matches `%s`.", len(packages), text)
+		if !std {
+			msg += " Try searching the standard library with `stdlib:true`."
+		}
+		return failEmbed("Error: Not Found", msg), nil
+	}
+
+	var sb strings.Builder
+	opts := make([]discord.SelectOption, 0, searchResults)
+	for _, hit := range hits[:min(len(hits), searchResults)] {
+		query := hit.sym.query()
+		fmt.Fprintf(&sb, "**[%s](%s)** %s\n> %s\n", query, searchURL(hit.sym), hit.sym.kindName(), hit.snippet)
+		opts = append(opts, discord.SelectOption{
+			Label:       query,
+			Value:       query,
+			Description: hit.sym.kindName(),
+		})
+	}
+
+	return discord.Embed{
+		Title:       "Search: " + text,
+		Description: sb.String(),
+		Footer: &discord.EmbedFooter{
+			Text: fmt.Sprintf("%d results in %d packages", len(hits), len(packages)),
+		},
+		Color: accentColor,
+	}, pickerComponents(id, opts)
+}
+
+// searchURL links to the documentation of the symbol on pkg.go.dev.
+func searchURL(sym indexedSymbol) string {
+	if sym.kind == kindPackage {
+		return "https://pkg.go.dev/" + sym.pkg
+	}
+	return "https://pkg.go.dev/" + sym.pkg + "#" + sym.name
+}
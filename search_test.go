@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchDocs(t *testing.T) {
+	symbols := []indexedSymbol{
+		{pkg: "net/http", name: "Server.Shutdown", kind: kindMethod, doc: "Shutdown gracefully shuts down the server without interrupting any active connections."},
+		{pkg: "net/http", name: "Server.Close", kind: kindMethod, doc: "Close immediately closes all active listeners. For a graceful shutdown, use Shutdown."},
+		{pkg: "os", name: "Exit", kind: kindFunc, doc: "Exit causes the current program to exit."},
+		{pkg: "net/http", name: "Server.RegisterOnShutdown", kind: kindMethod},
+	}
+
+	hits := searchDocs(`"Graceful  Shutdown"`, symbols)
+	if assert.Len(t, hits, 2) {
+		assert.Equal(t, "net/http.Server.Close", hits[0].sym.query())
+		assert.Equal(t, "Close immediately closes all active listeners. For a **graceful** **shutdown**, use **Shutdown**.", hits[0].snippet)
+		assert.Equal(t, "net/http.Server.Shutdown", hits[1].sym.query())
+	}
+
+	hits = searchDocs("shutdown", symbols)
+	if assert.Len(t, hits, 2) {
+		assert.Equal(t, "net/http.Server.Shutdown", hits[0].sym.query())
+		assert.Equal(t, "net/http.Server.Close", hits[1].sym.query())
+	}
+
+	assert.Empty(t, searchDocs("  ", symbols))
+}
+
+func TestSnippet(t *testing.T) {
+	text := "The quick brown fox jumps over the lazy dog, and then it runs away into the forest where nobody can ever find it again, not even the hunter with his dogs and his *special* tools."
+	lower := text
+	at := 143
+
+	assert.Equal(t,
+		"...nobody can ever find it again, not even the **hunter** with his dogs and his \\*special\\* tools.",
+		snippet(text, lower, at, []string{"hunter"}))
+}
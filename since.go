@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go/version"
+	"strings"
+
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+const invalidGoVersion = "`%s` is not a valid Go version, such as `go1.20`."
+
+// since returns the Go release that added a standard library symbol. Like
+// pkg.go.dev, symbols that exist since Go 1.0 return "go1".
+func (b *botState) since(sym symbol) string {
+	importPath, _ := sym.importPath()
+	if !gosrc.IsStdlib(importPath) {
+		return ""
+	}
+
+	switch sym.kind {
+	case kindType:
+		return b.api.Symbol(importPath, sym.typ.Name)
+	case kindFunc:
+		return b.api.Symbol(importPath, sym.fn.Name)
+	case kindConst, kindVar:
+		return b.api.Symbol(importPath, sym.v.Name)
+	case kindMethod:
+		return b.api.Symbol(importPath, sym.method.For+"."+sym.method.Name)
+	case kindField:
+		return b.api.Symbol(importPath, sym.typ.Name+"."+sym.field.name)
+	}
+	return b.api.Package(importPath)
+}
+
+// parseGoVersion parses a Go version given by a user, such as "1.20" or
+// "go1.20.3".
+func parseGoVersion(s string) (string, bool) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if !strings.HasPrefix(s, "go") {
+		s = "go" + s
+	}
+	return s, version.IsValid(s)
+}
+
+// versionWarning returns a warning if the symbol was added after the Go
+// version, which is not checked if empty.
+func (b *botState) versionWarning(sym symbol, goVersion string) (discord.EmbedField, bool) {
+	if goVersion == "" {
+		return discord.EmbedField{}, false
+	}
+
+	since := b.since(sym)
+	if since == "" || version.Compare(since, version.Lang(goVersion)) <= 0 {
+		return discord.EmbedField{}, false
+	}
+	return discord.EmbedField{
+		Name:  "⚠️ Requires " + since,
+		Value: fmt.Sprintf("`%s` was added in %s, and is not available in %s.", sym.title(), since, goVersion),
+	}, true
+}
+
+// interactionDocs renders the docs of the query of an interaction, with a
+// warning if it requires a newer Go version than the one of the query.
+func (b *botState) interactionDocs(ctx context.Context, user discord.User, data *interactionData) (discord.Embed, *symbol, bool) {
+	embed, sym, more := b.docs(ctx, user, data.query)
+	b.addVersionWarning(sym, data.goVersion, &embed)
+	return embed, sym, more
+}
+
+// interactionPage is like interactionDocs, but renders a page of the expanded
+// docs.
+func (b *botState) interactionPage(ctx context.Context, user discord.User, data *interactionData, page int) (discord.Embed, *symbol, int) {
+	embed, sym, pages := b.docsPage(ctx, user, data.query, page)
+	b.addVersionWarning(sym, data.goVersion, &embed)
+	return embed, sym, pages
+}
+
+// addVersionWarning adds the warning of versionWarning to the embed of the
+// symbol, which may be nil.
+func (b *botState) addVersionWarning(sym *symbol, goVersion string, embed *discord.Embed) {
+	if sym == nil {
+		return
+	}
+	if field, ok := b.versionWarning(*sym, goVersion); ok {
+		embed.Fields = append([]discord.EmbedField{field}, embed.Fields...)
+	}
+}
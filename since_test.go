@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DiscordGophers/dr-docso/goapi"
+	"github.com/hhhapz/doc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSince(t *testing.T) {
+	api := goapi.New()
+	require.NoError(t, api.Parse(strings.NewReader("pkg slices, func Contains[$0 interface{ ~[]$1 }, $1 comparable]($0, $1) bool #57433\n"), "go1.21"))
+	require.NoError(t, api.Parse(strings.NewReader("pkg bytes, method (*Buffer) Len() int\n"), "go1"))
+	b := botState{api: api}
+
+	slices := doc.Package{Name: "slices", URL: "slices"}
+	assert.Equal(t, "go1.21", b.since(symbol{pkg: slices}))
+	assert.Equal(t, "go1.21", b.since(symbol{pkg: slices, kind: kindFunc, fn: doc.Function{Name: "Contains"}}))
+
+	bytes := doc.Package{Name: "bytes", URL: "bytes"}
+	method := doc.Method{For: "Buffer", Function: doc.Function{Name: "Len"}}
+	assert.Equal(t, "go1", b.since(symbol{pkg: bytes, kind: kindMethod, method: method}))
+
+	other := doc.Package{Name: "example.com/slices", URL: "example.com/slices"}
+	assert.Equal(t, "", b.since(symbol{pkg: other, kind: kindFunc, fn: doc.Function{Name: "Contains"}}))
+}
+
+func TestParseGoVersion(t *testing.T) {
+	for in, want := range map[string]string{
+		"1.20":      "go1.20",
+		"go1.21.3":  "go1.21.3",
+		" Go1.22 ":  "go1.22",
+		"go1.21rc2": "go1.21rc2",
+	} {
+		got, ok := parseGoVersion(in)
+		assert.True(t, ok, in)
+		assert.Equal(t, want, got)
+	}
+
+	_, ok := parseGoVersion("latest")
+	assert.False(t, ok)
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+)
+
+const (
+	sourcePageLimit = 3500
+	sourceTimeout   = 10 * time.Second
+
+	sourceNotFound = "Could not find the source code of `%s`."
+	sourceErr      = "Could not load the source code of `%s`, please try again later."
+)
+
+var sourceOption = discord.SelectOption{
+	Label:       "View Source",
+	Value:       "source",
+	Description: "Show the source code of the declaration.",
+	Emoji:       &discord.ComponentEmoji{Name: "📄"},
+}
+
+// declSource is the source code of a single declaration.
+type declSource struct {
+	file string
+	line int
+	code string
+	url  string
+}
+
+// hasSource reports whether the source of the symbol can be displayed.
+func (s symbol) hasSource() bool {
+	return s.kind == kindType || s.kind == kindFunc || s.kind == kindMethod
+}
+
+// importPath returns the import path and version of the package of the
+// symbol. The import path is taken from the package name, as the queried
+// module may not be in its original case.
+func (s symbol) importPath() (string, string) {
+	importPath, _ := splitVersion(s.pkg.Name)
+	_, version := splitVersion(s.pkg.URL)
+	return importPath, version
+}
+
+// source loads the package of the symbol, and finds its declaration.
+func (b *botState) source(ctx context.Context, sym symbol) (declSource, error) {
+	importPath, version := sym.importPath()
+	if version == "latest" {
+		version = ""
+	}
+
+	pkg, err := b.sources.Load(ctx, importPath, version)
+	if err != nil {
+		return declSource{}, err
+	}
+
+	var recv, name string
+	switch sym.kind {
+	case kindType:
+		name = sym.typ.Name
+	case kindFunc:
+		name = sym.fn.Name
+	case kindMethod:
+		recv, name = sym.method.For, sym.method.Name
+	default:
+		return declSource{}, gosrc.ErrNotFound
+	}
+
+	src, ok := findDecl(pkg, recv, name)
+	if !ok {
+		return declSource{}, gosrc.ErrNotFound
+	}
+	src.url = sourceURL(pkg, src.file, src.line)
+	return src, nil
+}
+
+// findDecl finds the declaration of a type or function, or the method of a
+// type if recv is set. Files that are built on linux/amd64 are preferred over
+// other platforms.
+func findDecl(pkg *gosrc.Package, recv, name string) (declSource, bool) {
+	var fallback declSource
+	var found bool
+
+	for _, file := range pkg.Names() {
+		content := pkg.Files[file]
+
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, file, content, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+
+		node := declNode(f, recv, name)
+		if node == nil {
+			continue
+		}
+
+		start, end := fset.Position(node.Pos()), fset.Position(node.End())
+		code := string(content[start.Offset:end.Offset])
+		if _, ok := node.(*ast.TypeSpec); ok {
+			code = "type " + code
+		}
+
+		src := declSource{
+			file: file,
+			line: start.Line,
+			code: code,
+		}
+		if gosrc.MatchFile(file, content, true) {
+			return src, true
+		}
+		if !found {
+			fallback, found = src, true
+		}
+	}
+	return fallback, found
+}
+
+// declNode returns the declaration of name in the file. For types in a
+// grouped declaration, only the type spec is returned.
+func declNode(f *ast.File, recv, name string) ast.Node {
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Name.Name != name {
+				continue
+			}
+			if recv == "" && decl.Recv == nil {
+				return decl
+			}
+			if recv != "" && decl.Recv != nil && len(decl.Recv.List) > 0 && recvName(decl.Recv.List[0].Type) == recv {
+				return decl
+			}
+
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE || recv != "" {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				spec := spec.(*ast.TypeSpec)
+				if spec.Name.Name != name {
+					continue
+				}
+				if decl.Lparen.IsValid() {
+					return spec
+				}
+				return decl
+			}
+		}
+	}
+	return nil
+}
+
+// recvName returns the type name of a method receiver, such as T for *T or
+// T[K, V].
+func recvName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// sourceURL links to the line of the file in the repository browser of the
+// module. Modules that are not hosted on a known site link to pkg.go.dev.
+func sourceURL(pkg *gosrc.Package, file string, line int) string {
+	if pkg.Module == "std" {
+		return fmt.Sprintf("https://cs.opensource.google/go/go/+/%s:%s;l=%d",
+			pkg.Version, path.Join(pkg.Dir, file), line)
+	}
+
+	// Tags of nested modules are prefixed with the module directory,
+	// and pseudo-versions end with the commit hash.
+	ref, tagged := strings.TrimSuffix(pkg.Version, "+incompatible"), true
+	if split := strings.Split(ref, "-"); len(split) >= 3 && len(split[len(split)-1]) == 12 {
+		ref, tagged = split[len(split)-1], false
+	}
+
+	host, rest, _ := strings.Cut(pkg.Module, "/")
+	repoParts := strings.SplitN(rest, "/", 3)
+	if len(repoParts) < 2 {
+		return "https://pkg.go.dev/" + pkg.ImportPath + "@" + pkg.Version
+	}
+	repo := host + "/" + repoParts[0] + "/" + repoParts[1]
+
+	// The module may be in a subdirectory of the repository, such as
+	// github.com/owner/repo/sub, or a major version suffix like /v2.
+	subdir := strings.TrimPrefix(strings.TrimPrefix(pkg.Module, repo), "/")
+	if isMajorSuffix(subdir) {
+		subdir = ""
+	} else if dir, last := path.Split(subdir); isMajorSuffix(last) {
+		subdir = strings.TrimSuffix(dir, "/")
+	}
+	if subdir != "" && tagged {
+		ref = subdir + "/" + ref
+	}
+
+	file = path.Join(subdir, pkg.Dir, file)
+	switch host {
+	case "github.com":
+		return fmt.Sprintf("https://%s/blob/%s/%s#L%d", repo, ref, file, line)
+	case "gitlab.com":
+		return fmt.Sprintf("https://%s/-/blob/%s/%s#L%d", repo, ref, file, line)
+	case "bitbucket.org":
+		return fmt.Sprintf("https://%s/src/%s/%s#lines-%d", repo, ref, file, line)
+	}
+	return "https://pkg.go.dev/" + pkg.ImportPath + "@" + pkg.Version
+}
+
+func isMajorSuffix(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	n, err := strconv.Atoi(s[1:])
+	return err == nil && n >= 2
+}
+
+// handleSource displays the source of the query privately.
+func (b *botState) handleSource(e *gateway.InteractionCreateEvent, data *interactionData) {
+	b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+		Type: api.DeferredMessageInteractionWithSource,
+		Data: &api.InteractionResponseData{Flags: discord.EphemeralMessage},
+	})
+	b.respondSource(e, data, 0)
+}
+
+// handleSourceComponent handles the page buttons of a source message. The
+// command is formatted as "<id>.<page>".
+func (b *botState) handleSourceComponent(e *gateway.InteractionCreateEvent, cmd string) {
+	id, p, _ := strings.Cut(cmd, ".")
+	page, _ := strconv.Atoi(p)
+
+	mu.Lock()
+	data, ok := interactionMap[id]
+	mu.Unlock()
+	if !ok {
+		b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+			Type: api.UpdateMessage,
+			Data: &api.InteractionResponseData{
+				Embeds:     &[]discord.Embed{failEmbed("Error", expired)},
+				Components: &discord.ContainerComponents{},
+			},
+		})
+		return
+	}
+
+	b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+		Type: api.DeferredMessageUpdate,
+	})
+	b.respondSource(e, data, page)
+}
+
+// respondSource edits the deferred response to show a page of the source of
+// the query.
+func (b *botState) respondSource(e *gateway.InteractionCreateEvent, data *interactionData, page int) {
+	log.Printf("%s used docs source(%q, %d)", e.User.Tag(), data.query, page)
+
+	ctx, cancel := context.WithTimeout(context.Background(), searchTimeout)
+	defer cancel()
+	embed, comps := b.sourceEmbed(ctx, *e.User, data, page)
+	if _, err := b.state.EditInteractionResponse(e.AppID, e.Token, api.EditInteractionResponseData{
+		Embeds:     &[]discord.Embed{embed},
+		Components: &comps,
+	}); err != nil {
+		log.Printf("could not send interaction callback, %v", err)
+	}
+}
+
+func (b *botState) sourceEmbed(ctx context.Context, user discord.User, data *interactionData, page int) (discord.Embed, discord.ContainerComponents) {
+	sym, err := b.lookup(ctx, user, data.query)
+	if err != nil {
+		return failEmbed("Error", err.Error()), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sourceTimeout)
+	defer cancel()
+
+	src, err := b.source(ctx, sym)
+	if err != nil {
+		log.Printf("Source request by %s(%q) failed: %v", user.Tag(), data.query, err)
+		msg := fmt.Sprintf(sourceNotFound, data.query)
+		if !errors.Is(err, gosrc.ErrNotFound) {
+			msg = fmt.Sprintf(sourceErr, data.query)
+		}
+		return failEmbed("Error", msg), nil
+	}
+
+	pages := codePages(src.code, sourcePageLimit)
+	if page < 0 || page >= len(pages) {
+		page = 0
+	}
+
+	var comps discord.ContainerComponents
+	if len(pages) > 1 {
+		prefix := fmt.Sprintf("docs.source.%s.", data.id)
+		comps = discord.ContainerComponents{
+			pageButtons(prefix, page, len(pages)),
+		}
+	}
+
+	return discord.Embed{
+		Title:       "Source: " + sym.title(),
+		URL:         src.url,
+		Description: pages[page],
+		Footer: &discord.EmbedFooter{
+			Text: fmt.Sprintf("%s:%d\nPage %d of %d", src.file, src.line, page+1, len(pages)),
+		},
+		Color: accentColor,
+	}, comps
+}
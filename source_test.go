@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindDecl(t *testing.T) {
+	pkg := &gosrc.Package{
+		Files: map[string][]byte{
+			"a_windows.go": []byte("package a\n\nfunc Open() {}\n"),
+			"a_linux.go":   []byte("package a\n\n// Open opens.\nfunc Open() {\n\tprintln()\n}\n"),
+			"b.go": []byte(`package a
+
+type (
+	// Reader reads.
+	Reader[T any] struct{}
+
+	Writer int
+)
+
+type Closer interface{ Close() error }
+
+func (r *Reader[T]) Read() {}
+func (w Writer) Read() {}
+`),
+		},
+	}
+
+	cases := []struct {
+		recv, name string
+		file       string
+		line       int
+		code       string
+	}{
+		{"", "Open", "a_linux.go", 4, "func Open() {\n\tprintln()\n}"},
+		{"", "Reader", "b.go", 5, "type Reader[T any] struct{}"},
+		{"", "Closer", "b.go", 10, "type Closer interface{ Close() error }"},
+		{"Reader", "Read", "b.go", 12, "func (r *Reader[T]) Read() {}"},
+		{"Writer", "Read", "b.go", 13, "func (w Writer) Read() {}"},
+	}
+	for _, c := range cases {
+		src, ok := findDecl(pkg, c.recv, c.name)
+		if assert.True(t, ok, c.name) {
+			assert.Equal(t, c.file, src.file)
+			assert.Equal(t, c.line, src.line)
+			assert.Equal(t, c.code, src.code)
+		}
+	}
+
+	_, ok := findDecl(pkg, "Closer", "Close")
+	assert.False(t, ok)
+}
+
+func TestSourceURL(t *testing.T) {
+	cases := []struct {
+		pkg  gosrc.Package
+		want string
+	}{
+		{
+			gosrc.Package{ImportPath: "fmt", Module: "std", Version: "go1.22.3", Dir: "src/fmt"},
+			"https://cs.opensource.google/go/go/+/go1.22.3:src/fmt/print.go;l=10",
+		},
+		{
+			gosrc.Package{ImportPath: "github.com/a/b/v2/c", Module: "github.com/a/b/v2", Version: "v2.1.0", Dir: "c"},
+			"https://github.com/a/b/blob/v2.1.0/c/print.go#L10",
+		},
+		{
+			gosrc.Package{ImportPath: "github.com/a/b/sub", Module: "github.com/a/b/sub", Version: "v0.3.0"},
+			"https://github.com/a/b/blob/sub/v0.3.0/sub/print.go#L10",
+		},
+		{
+			gosrc.Package{ImportPath: "golang.org/x/sync", Module: "golang.org/x/sync", Version: "v0.0.0-20210220032951-036812b2e83c"},
+			"https://pkg.go.dev/golang.org/x/sync@v0.0.0-20210220032951-036812b2e83c",
+		},
+		{
+			gosrc.Package{ImportPath: "gitlab.com/a/b", Module: "gitlab.com/a/b", Version: "v0.0.0-20210220032951-036812b2e83c"},
+			"https://gitlab.com/a/b/-/blob/036812b2e83c/print.go#L10",
+		},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, sourceURL(&c.pkg, "print.go", 10))
+	}
+}
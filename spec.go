@@ -16,25 +16,25 @@ func (b *botState) handleSpec(e *gateway.InteractionCreateEvent, d *discord.Comm
 
 	log.Printf("%s used spec(%q)", e.User.Tag(), query)
 
+	b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+		Type: api.MessageInteractionWithSource,
+		Data: specResponse(query),
+	})
+}
+
+// specResponse returns the response to a spec query, which is shared by the
+// slash and text commands.
+func specResponse(query string) *api.InteractionResponseData {
 	if len(query) < 3 || len(query) > 60 {
-		embed := failEmbed("Error", "Your query must be between 3 and 60 characters.")
-		b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
-			Type: api.MessageInteractionWithSource,
-			Data: &api.InteractionResponseData{
-				Flags:  discord.EphemeralMessage,
-				Embeds: &[]discord.Embed{embed},
-			},
-		})
-		return
+		return &api.InteractionResponseData{
+			Flags:  discord.EphemeralMessage,
+			Embeds: &[]discord.Embed{failEmbed("Error", "Your query must be between 3 and 60 characters.")},
+		}
 	}
 
 	switch query {
 	case "toc", "contents", "list":
-		b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
-			Type: api.MessageInteractionWithSource,
-			Data: spec.TOC,
-		})
-		return
+		return spec.TOC
 	}
 
 	nodes := spec.Cache.Search(query)
@@ -52,18 +52,14 @@ func (b *botState) handleSpec(e *gateway.InteractionCreateEvent, d *discord.Comm
 			}
 		}
 
-		b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
-			Type: api.MessageInteractionWithSource,
-			Data: &api.InteractionResponseData{
-				Flags: discord.EphemeralMessage,
-				Embeds: &[]discord.Embed{failEmbed(
-					"Error",
-					fmt.Sprintf("An exact match was not found for %q.\n\nTry `/spec query:toc`.\n%s", query, results),
-				)},
-				Components: spec.NodesSelect(nodes),
-			},
-		})
-		return
+		return &api.InteractionResponseData{
+			Flags: discord.EphemeralMessage,
+			Embeds: &[]discord.Embed{failEmbed(
+				"Error",
+				fmt.Sprintf("An exact match was not found for %q.\n\nTry `/spec query:toc`.\n%s", query, results),
+			)},
+			Components: spec.NodesSelect(nodes),
+		}
 	}
 
 	// TODO: Show components if more than one result.
@@ -71,21 +67,22 @@ func (b *botState) handleSpec(e *gateway.InteractionCreateEvent, d *discord.Comm
 	node := nodes[0]
 	md, _ := node.Render(1000)
 
-	b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
-		Type: api.MessageInteractionWithSource,
-		Data: &api.InteractionResponseData{
-			Embeds: &[]discord.Embed{
-				{
-					Title:       fmt.Sprintf("Spec: %q", query),
-					Description: md,
-					Color:       accentColor,
-				},
+	return &api.InteractionResponseData{
+		Embeds: &[]discord.Embed{
+			{
+				Title:       fmt.Sprintf("Spec: %q", query),
+				Description: md,
+				Color:       accentColor,
 			},
 		},
-	})
+	}
 }
 
 func (b *botState) handleSpecComponent(e *gateway.InteractionCreateEvent, data discord.ComponentInteraction, cmd string) {
+	if !b.checkTextOwner(e) {
+		return
+	}
+
 	switch cmd {
 	case "toc":
 		opt := data.(*discord.StringSelectInteraction).Values[0]
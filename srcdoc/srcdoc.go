@@ -0,0 +1,220 @@
+// Package srcdoc builds the documentation of Go packages from their source
+// files with go/doc, in the format of github.com/hhhapz/doc. Unlike scraping
+// pkg.go.dev, it works offline when the sources are available locally.
+package srcdoc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/doc/comment"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strings"
+
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	hdoc "github.com/hhhapz/doc"
+)
+
+// Searcher builds the documentation of the packages returned by a loader. Like
+// the searchers of hhhapz/doc, the names in the maps of a package are
+// lowercased. Functions that return a type are listed both with the type and
+// with the package, as with doc.WithDuplicateTypeFuncs.
+type Searcher struct {
+	loader gosrc.Loader
+}
+
+var _ hdoc.Searcher = (*Searcher)(nil)
+
+// New creates a searcher that loads the sources of packages with the loader.
+func New(loader gosrc.Loader) *Searcher {
+	return &Searcher{loader: loader}
+}
+
+// Search loads and documents the package. The module may have a version
+// suffix, such as "@v1.2.3"; "@latest" is the same as no version.
+func (s *Searcher) Search(ctx context.Context, module string) (hdoc.Package, error) {
+	importPath, version, _ := strings.Cut(module, "@")
+	if version == "latest" {
+		version = ""
+	}
+
+	src, err := s.loader.Load(ctx, importPath, version)
+	if err != nil {
+		return hdoc.Package{}, err
+	}
+	return Build(src)
+}
+
+// Build builds the documentation of a package. Only the files that are built
+// on linux/amd64 are documented, like on pkg.go.dev.
+func Build(src *gosrc.Package) (hdoc.Package, error) {
+	fset := token.NewFileSet()
+	files := map[string]*ast.File{}
+	var list []*ast.File
+	for _, name := range src.Names() {
+		data := src.Files[name]
+		if !gosrc.MatchFile(name, data, true) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, data, parser.ParseComments)
+		if err != nil {
+			return hdoc.Package{}, err
+		}
+		files[name] = f
+		list = append(list, f)
+	}
+	if len(list) == 0 {
+		return hdoc.Package{}, fmt.Errorf("%s has no files for linux/amd64: %w", src.ImportPath, gosrc.ErrNotFound)
+	}
+
+	p, err := doc.NewFromFiles(fset, list, src.ImportPath)
+	if err != nil {
+		return hdoc.Package{}, err
+	}
+
+	b := builder{fset: fset, files: files, pkg: p}
+	pkg := hdoc.Package{
+		URL:         src.ImportPath,
+		Name:        p.Name,
+		Overview:    b.comment(p.Doc),
+		ConstantMap: map[string]hdoc.Variable{},
+		VariableMap: map[string]hdoc.Variable{},
+		Functions:   map[string]hdoc.Function{},
+		Types:       map[string]hdoc.Type{},
+		Subpackages: src.Subpackages,
+	}
+
+	values := func(consts, vars []*doc.Value) {
+		for _, v := range consts {
+			pkg.Constants = append(pkg.Constants, b.values(v, pkg.ConstantMap))
+		}
+		for _, v := range vars {
+			pkg.Variables = append(pkg.Variables, b.values(v, pkg.VariableMap))
+		}
+	}
+	values(p.Consts, p.Vars)
+
+	for _, fn := range p.Funcs {
+		pkg.Functions[strings.ToLower(fn.Name)] = b.function(fn)
+	}
+	for _, t := range p.Types {
+		typ := hdoc.Type{
+			Name:          t.Name,
+			Signature:     b.print(t.Decl),
+			Comment:       b.comment(t.Doc),
+			TypeFunctions: map[string]hdoc.Function{},
+			Methods:       map[string]hdoc.Method{},
+		}
+		for _, fn := range t.Funcs {
+			f := b.function(fn)
+			typ.TypeFunctions[strings.ToLower(fn.Name)] = f
+			pkg.Functions[strings.ToLower(fn.Name)] = f
+		}
+		for _, m := range t.Methods {
+			typ.Methods[strings.ToLower(m.Name)] = hdoc.Method{
+				For:      t.Name,
+				Function: b.function(m),
+			}
+		}
+		pkg.Types[strings.ToLower(t.Name)] = typ
+		values(t.Consts, t.Vars)
+	}
+	return pkg, nil
+}
+
+// builder converts the declarations and comments of a package.
+type builder struct {
+	fset  *token.FileSet
+	files map[string]*ast.File
+	pkg   *doc.Package
+}
+
+// values converts a group of constants or variables, adding each name in the
+// group to m.
+func (b builder) values(v *doc.Value, m map[string]hdoc.Variable) hdoc.Variable {
+	group := hdoc.Variable{
+		Signature: b.print(v.Decl),
+		Comment:   b.comment(v.Doc),
+	}
+	for _, name := range v.Names {
+		named := group
+		named.Name = name
+		m[strings.ToLower(name)] = named
+	}
+	return group
+}
+
+func (b builder) function(fn *doc.Func) hdoc.Function {
+	decl := *fn.Decl
+	decl.Doc, decl.Body = nil, nil
+	return hdoc.Function{
+		Name:      fn.Name,
+		Signature: b.print(&decl),
+		Comment:   b.comment(fn.Doc),
+	}
+}
+
+// print formats a declaration with the comments inside of it, such as the
+// comments of struct fields.
+func (b builder) print(node ast.Node) string {
+	var comments []*ast.CommentGroup
+	if f, ok := b.files[b.fset.File(node.Pos()).Name()]; ok {
+		comments = f.Comments
+	}
+
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(&buf, b.fset, &printer.CommentedNode{Node: node, Comments: comments}); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// comment converts a doc comment into the notes of hhhapz/doc. Like the
+// pkg.go.dev parser, the text of paragraphs is joined into a single line.
+// Lists are converted to paragraphs with an item on each line.
+func (b builder) comment(text string) hdoc.Comment {
+	if text == "" {
+		return nil
+	}
+
+	printer := b.pkg.Printer()
+	printer.TextWidth = -1
+	inline := func(text []comment.Text) string {
+		out := printer.Text(&comment.Doc{Content: []comment.Block{&comment.Paragraph{Text: text}}})
+		return strings.Join(strings.Fields(string(out)), " ")
+	}
+
+	var notes hdoc.Comment
+	for _, block := range b.pkg.Parser().Parse(text).Content {
+		switch block := block.(type) {
+		case *comment.Paragraph:
+			notes = append(notes, hdoc.Paragraph(inline(block.Text)))
+		case *comment.Heading:
+			notes = append(notes, hdoc.Heading(inline(block.Text)))
+		case *comment.Code:
+			notes = append(notes, hdoc.Pre(block.Text))
+		case *comment.List:
+			var items []string
+			for _, item := range block.Items {
+				bullet := "-"
+				if item.Number != "" {
+					bullet = item.Number + "."
+				}
+				var parts []string
+				for _, c := range item.Content {
+					if p, ok := c.(*comment.Paragraph); ok {
+						parts = append(parts, inline(p.Text))
+					}
+				}
+				items = append(items, bullet+" "+strings.Join(parts, " "))
+			}
+			notes = append(notes, hdoc.Paragraph(strings.Join(items, "\n")))
+		}
+	}
+	return notes
+}
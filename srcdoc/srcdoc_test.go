@@ -0,0 +1,118 @@
+package srcdoc
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	hdoc "github.com/hhhapz/doc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const source = `// Package shapes draws shapes.
+//
+// # Usage
+//
+// Create a shape:
+//
+//	s := shapes.NewSquare(2)
+//
+// Shapes can be:
+//   - squares
+//   - circles
+package shapes
+
+// Sides of shapes.
+const (
+	Triangle = 3
+	Square   = 4
+)
+
+// ErrNegative is returned for negative sizes.
+var ErrNegative = errors.New("negative size")
+
+// Shape is a shape.
+type Shape struct {
+	// Size is the length of a side.
+	Size int // in pixels
+	name string
+}
+
+// NewSquare returns a square. See [Shape.Area].
+func NewSquare(size int) *Shape {
+	return &Shape{Size: size}
+}
+
+// Area returns the area of the shape.
+func (s *Shape) Area() int { return s.Size * s.Size }
+
+// Draw draws a shape.
+func Draw(s Shape) {}
+`
+
+type loader map[string]*gosrc.Package
+
+func (l loader) Load(ctx context.Context, importPath, version string) (*gosrc.Package, error) {
+	pkg, ok := l[importPath+"@"+version]
+	if !ok {
+		return nil, gosrc.ErrNotFound
+	}
+	return pkg, nil
+}
+
+func TestSearch(t *testing.T) {
+	s := New(loader{"example.com/shapes@v1.0.0": {
+		ImportPath:  "example.com/shapes",
+		Files:       map[string][]byte{"shapes.go": []byte(source), "shapes_windows.go": []byte("package shapes\n\nfunc Windows() {}\n")},
+		Subpackages: []string{"example.com/shapes/fill"},
+	}})
+
+	pkg, err := s.Search(context.Background(), "example.com/shapes@v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/shapes", pkg.URL)
+	assert.Equal(t, "shapes", pkg.Name)
+	assert.Equal(t, []string{"example.com/shapes/fill"}, pkg.Subpackages)
+	assert.Equal(t, hdoc.Comment{
+		hdoc.Paragraph("Package shapes draws shapes."),
+		hdoc.Heading("Usage"),
+		hdoc.Paragraph("Create a shape:"),
+		hdoc.Pre("s := shapes.NewSquare(2)\n"),
+		hdoc.Paragraph("Shapes can be:"),
+		hdoc.Paragraph("- squares\n- circles"),
+	}, pkg.Overview)
+
+	assert.Len(t, pkg.Constants, 1)
+	assert.Equal(t, "Square", pkg.ConstantMap["square"].Name)
+	assert.Contains(t, pkg.ConstantMap["square"].Signature, "Triangle = 3")
+	assert.Equal(t, "ErrNegative", pkg.VariableMap["errnegative"].Name)
+
+	shape := pkg.Types["shape"]
+	assert.Equal(t, "type Shape struct {\n\t// Size is the length of a side.\n\tSize int // in pixels\n\t// contains filtered or unexported fields\n}", shape.Signature)
+	assert.Equal(t, hdoc.Comment{hdoc.Paragraph("Shape is a shape.")}, shape.Comment)
+	assert.Equal(t, "func (s *Shape) Area() int", shape.Methods["area"].Signature)
+	assert.Equal(t, "Shape", shape.Methods["area"].For)
+	assert.Equal(t, "func NewSquare(size int) *Shape", shape.TypeFunctions["newsquare"].Signature)
+	assert.Equal(t, hdoc.Paragraph("NewSquare returns a square. See Shape.Area."), pkg.Functions["newsquare"].Comment[0])
+	assert.Contains(t, pkg.Functions, "draw")
+	assert.NotContains(t, pkg.Functions, "windows")
+
+	_, err = s.Search(context.Background(), "example.com/shapes")
+	assert.True(t, errors.Is(err, gosrc.ErrNotFound))
+}
+
+func TestSearchGOROOT(t *testing.T) {
+	root := gosrc.GOROOT(runtime.GOROOT())
+	if _, err := root.Version(); err != nil {
+		t.Skip("GOROOT has no VERSION file")
+	}
+
+	pkg, err := New(root).Search(context.Background(), "strings@latest")
+	require.NoError(t, err)
+	assert.Equal(t, "strings", pkg.URL)
+	assert.Contains(t, pkg.Types, "builder")
+	assert.Contains(t, pkg.Types["reader"].TypeFunctions, "newreader")
+	assert.Contains(t, pkg.Functions, "newreader")
+}
@@ -1,50 +1,170 @@
 package main
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hhhapz/doc"
 	"github.com/lithammer/fuzzysearch/fuzzy"
 )
 
-func packageOptions(parts []string, pkg doc.Package) fuzzy.Ranks {
-	var opts []string
+// packageOption is an autocomplete choice for an item of a package.
+type packageOption struct {
+	// name is the query of the item in its package, such as Client.Do.
+	name       string
+	kind       symbolKind
+	signature  string
+	deprecated bool
+	// ctor is set for functions that return a type of the package.
+	ctor bool
+
+	match    int
+	distance int
+}
+
+// Matches of the name of an option, best first.
+const (
+	matchExact = iota
+	matchExactFold
+	matchPrefix
+	matchPrefixFold
+	matchFuzzy
+)
+
+// choice returns the name of the autocomplete choice, with the kind and a
+// preview of the signature of the item.
+func (o packageOption) choice() string {
+	// Options that are not items, such as the package info, have no kind.
+	if o.kind == kindPackage {
+		return truncate(o.name, 100)
+	}
+	kind := o.kind.String()
+	if o.deprecated {
+		kind += ", deprecated"
+	}
+	return truncate(strings.TrimSpace(fmt.Sprintf("%s (%s) %s", o.name, kind, o.signature)), 100)
+}
+
+// order ranks the kinds of options, so that types and their constructors are
+// listed before other functions, methods and values.
+func (o packageOption) order() int {
+	switch {
+	case o.kind == kindType:
+		return 0
+	case o.ctor:
+		return 1
+	case o.kind == kindFunc:
+		return 2
+	case o.kind == kindMethod:
+		return 3
+	}
+	return 4
+}
+
+// packageOptions returns the items of the package that match the name, for
+// autocompletion. Exact and prefix matches are ranked before fuzzy ones, and
+// case sensitive matches before others. Deprecated items are ranked last.
+func packageOptions(name string, pkg doc.Package) []packageOption {
+	ctors := map[string]bool{}
+	for _, t := range pkg.Types {
+		for _, fn := range t.TypeFunctions {
+			ctors[fn.Name] = true
+		}
+	}
+
+	var opts []packageOption
+	add := func(item string, kind symbolKind, signature string, comment doc.Comment) {
+		opt := packageOption{
+			name:       item,
+			kind:       kind,
+			signature:  signaturePreview(signature, item),
+			deprecated: deprecation(comment.Text()) != "",
+			ctor:       kind == kindFunc && ctors[item],
+		}
+
+		lowerItem, lowerName := strings.ToLower(item), strings.ToLower(name)
+		switch {
+		case item == name:
+			opt.match = matchExact
+		case lowerItem == lowerName:
+			opt.match = matchExactFold
+		case strings.HasPrefix(item, name):
+			opt.match = matchPrefix
+		case strings.HasPrefix(lowerItem, lowerName):
+			opt.match = matchPrefixFold
+		case fuzzy.Match(lowerName, lowerItem):
+			opt.match = matchFuzzy
+			opt.distance = fuzzy.LevenshteinDistance(lowerName, lowerItem)
+		default:
+			return
+		}
+		opts = append(opts, opt)
+	}
 
 	for _, c := range pkg.Constants {
-		opts = append(opts, c.Name)
+		add(c.Name, kindConst, c.Signature, c.Comment)
 	}
 	for _, v := range pkg.Variables {
-		opts = append(opts, v.Name)
+		add(v.Name, kindVar, v.Signature, v.Comment)
 	}
 	for _, f := range pkg.Functions {
-		opts = append(opts, f.Name)
+		add(f.Name, kindFunc, f.Signature, f.Comment)
 	}
 	for _, t := range pkg.Types {
-		opts = append(opts, t.Name)
+		add(t.Name, kindType, t.Signature, t.Comment)
 		for _, m := range t.Methods {
-			opts = append(opts, t.Name+"."+m.Name)
+			add(t.Name+"."+m.Name, kindMethod, m.Signature, m.Comment)
 		}
 	}
 
-	joined := strings.Join(parts, ".")
+	sort.Slice(opts, func(i, j int) bool {
+		a, b := opts[i], opts[j]
+		switch {
+		case a.deprecated != b.deprecated:
+			return b.deprecated
+		case a.match != b.match:
+			return a.match < b.match
+		case a.order() != b.order():
+			return a.order() < b.order()
+		case a.distance != b.distance:
+			return a.distance < b.distance
+		}
+		return a.name < b.name
+	})
 
-	ranks := fuzzy.RankFindFold(joined, opts)
 	switch {
-	case joined == "":
-		ranks = append([]fuzzy.Rank{
-			{
-				Source: "<package info>",
-				Target: "<pkginfo>",
-			},
-		}, ranks...)
-
-	case len(ranks) == 0:
-		ranks = append(ranks, fuzzy.Rank{
-			Source: joined,
-			Target: joined,
-		})
+	case name == "":
+		opts = append([]packageOption{{name: "<pkginfo>"}}, opts...)
+	case len(opts) == 0:
+		opts = append(opts, packageOption{name: name})
+	}
+	return opts
+}
+
+// signaturePreview returns the line of the signature that declares the item,
+// without its keyword and with its whitespace collapsed, such as
+// "(c *Client) Do(req *Request) (*Response, error)" for a method.
+func signaturePreview(signature, item string) string {
+	_, name, ok := strings.Cut(item, ".")
+	if !ok {
+		name = item
+	}
+
+	lines := strings.Split(signature, "\n")
+	line := lines[0]
+	for _, l := range lines {
+		if strings.Contains(l, name) {
+			line = l
+			break
+		}
+	}
+
+	line = strings.Join(strings.Fields(line), " ")
+	for _, keyword := range []string{"func ", "type ", "const ", "var "} {
+		line = strings.TrimPrefix(line, keyword)
 	}
-	return ranks
+	return strings.TrimSuffix(strings.TrimSuffix(line, " {"), " (")
 }
 
 func (b *botState) packageCache(query string) fuzzy.Ranks {
@@ -87,7 +207,7 @@ func (b *botState) packageCache(query string) fuzzy.Ranks {
 			OriginalIndex: -1,
 		})
 	}
-	return ranks
+	return b.indexRanks(query, packages, ranks)
 }
 
 var stdlibAliases = map[string]string{
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hhhapz/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageOptions(t *testing.T) {
+	pkg := doc.Package{
+		Functions: map[string]doc.Function{
+			"newreader": {Name: "NewReader", Signature: "func NewReader(rd io.Reader) *Reader"},
+			"read":      {Name: "Read", Signature: "func Read(r io.Reader) error"},
+			"readrune":  {Name: "ReadRune", Signature: "func ReadRune(r io.Reader) (rune, error)"},
+		},
+		Types: map[string]doc.Type{
+			"reader": {
+				Name:      "Reader",
+				Signature: "type Reader struct {\n\t// contains filtered or unexported fields\n}",
+				TypeFunctions: map[string]doc.Function{
+					"newreader": {Name: "NewReader"},
+				},
+				Methods: map[string]doc.Method{
+					"read": {Function: doc.Function{Name: "Read", Signature: "func (b *Reader) Read(p []byte) (n int, err error)"}},
+				},
+			},
+		},
+		ConstantMap: map[string]doc.Variable{},
+		Constants: []doc.Variable{
+			{Name: "ReadLimit", Signature: "const (\n\tReadLimit = 10\n\tWriteLimit = 20\n)"},
+			{Name: "READER", Signature: "const READER = 1"},
+		},
+	}
+
+	names := func(opts []packageOption) []string {
+		var names []string
+		for _, opt := range opts {
+			names = append(names, opt.name)
+		}
+		return names
+	}
+
+	// Exact matches come first, then prefix matches, with types and
+	// constructors before other items, and then fuzzy matches.
+	assert.Equal(t, []string{"Read", "Reader", "ReadRune", "Reader.Read", "ReadLimit", "READER", "NewReader"},
+		names(packageOptions("Read", pkg)))
+	assert.Equal(t, []string{"Reader", "READER", "Reader.Read", "NewReader"}, names(packageOptions("reader", pkg)))
+	// Case sensitive matches come before others.
+	assert.Equal(t, []string{"READER", "Reader"}, names(packageOptions("READER", pkg))[:2])
+
+	opts := packageOptions("Reader.Read", pkg)
+	assert.Equal(t, "Reader.Read (method) (b *Reader) Read(p []byte) (n int, err error)", opts[0].choice())
+
+	opts = packageOptions("", pkg)
+	assert.Equal(t, "<pkginfo>", opts[0].choice())
+	assert.Equal(t, []string{"Reader", "NewReader", "Read", "ReadRune"}, names(opts[1:5]))
+
+	assert.Equal(t, []packageOption{{name: "Missing"}}, packageOptions("Missing", pkg))
+}
+
+func TestSignaturePreview(t *testing.T) {
+	assert.Equal(t, "NewReader(rd io.Reader) *Reader", signaturePreview("func NewReader(rd io.Reader) *Reader", "NewReader"))
+	assert.Equal(t, "Reader struct", signaturePreview("type Reader struct {\n\tbuf []byte\n}", "Reader"))
+	assert.Equal(t, "WriteLimit = 20", signaturePreview("const (\n\tReadLimit = 10\n\tWriteLimit = 20\n)", "WriteLimit"))
+	assert.Equal(t, "(b *Reader) Read(p []byte) (n int, err error)", signaturePreview("func (b *Reader) Read(p []byte) (n int, err error)", "Reader.Read"))
+}
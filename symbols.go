@@ -0,0 +1,474 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/hhhapz/doc"
+	"github.com/lithammer/fuzzysearch/fuzzy"
+)
+
+// maxSymbols is the number of candidates shown in the symbol picker, which is
+// the most options a select menu can have.
+const maxSymbols = 25
+
+// symbolIndexWait is how long a query waits for the standard library to be
+// indexed after a start, so that enough of its deadline is left to look up the
+// symbol.
+const symbolIndexWait = 5 * time.Second
+
+// indexedSymbol is an exported symbol of a package. Methods are named
+// "<type>.<method>", and the package itself has no name.
+type indexedSymbol struct {
+	pkg  string
+	name string
+	kind symbolKind
+	doc  string
+}
+
+// query returns the docs query of the symbol.
+func (s indexedSymbol) query() string {
+	if s.name == "" {
+		return s.pkg
+	}
+	return s.pkg + "." + s.name
+}
+
+func (s indexedSymbol) kindName() string {
+	return s.kind.String()
+}
+
+func (k symbolKind) String() string {
+	switch k {
+	case kindPackage:
+		return "package"
+	case kindMethod:
+		return "method"
+	case kindType:
+		return "type"
+	case kindFunc:
+		return "func"
+	case kindConst:
+		return "const"
+	case kindVar:
+		return "var"
+	}
+	return ""
+}
+
+// symbolIndex indexes the symbols of the standard library, so that they can
+// be found without naming their package. The standard library is parsed from
+// source once, as fetching the docs of every package would take too long.
+type symbolIndex struct {
+	loader gosrc.Loader
+
+	once  sync.Once
+	ready chan struct{}
+	std   []indexedSymbol
+}
+
+func newSymbolIndex(loader gosrc.Loader) *symbolIndex {
+	return &symbolIndex{
+		loader: loader,
+		ready:  make(chan struct{}),
+	}
+}
+
+// build parses the standard library packages. It only runs once, and blocks
+// until the index is built. It is started in the background on start.
+func (idx *symbolIndex) build() {
+	idx.once.Do(func() {
+		defer close(idx.ready)
+
+		start := time.Now()
+		for lib := range stdlib {
+			if skipStdlib(lib) {
+				continue
+			}
+			pkg, err := idx.loader.Load(context.Background(), lib, "")
+			if err != nil {
+				continue
+			}
+			idx.std = append(idx.std, packageSymbols(pkg)...)
+		}
+		log.Printf("Indexed %d standard library symbols in %s", len(idx.std), time.Since(start))
+	})
+}
+
+// stdlib returns the standard library symbols, or nil if the index is not
+// built yet.
+func (idx *symbolIndex) stdlib() []indexedSymbol {
+	select {
+	case <-idx.ready:
+		return idx.std
+	default:
+		return nil
+	}
+}
+
+// wait waits until the index is built, for at most symbolIndexWait or until
+// ctx is done.
+func (idx *symbolIndex) wait(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, symbolIndexWait)
+	defer cancel()
+	select {
+	case <-idx.ready:
+	case <-ctx.Done():
+	}
+}
+
+// packageSymbols returns the package and its exported symbols, with their doc
+// comments, declared in the files of the package that are built on
+// linux/amd64.
+func packageSymbols(pkg *gosrc.Package) []indexedSymbol {
+	symbols := []indexedSymbol{{pkg: pkg.ImportPath, kind: kindPackage}}
+	seen := map[string]bool{}
+	add := func(name string, kind symbolKind, docs ...*ast.CommentGroup) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		symbols = append(symbols, indexedSymbol{
+			pkg:  pkg.ImportPath,
+			name: name,
+			kind: kind,
+			doc:  docText(docs...),
+		})
+	}
+
+	fset := token.NewFileSet()
+	for _, name := range pkg.Names() {
+		content := pkg.Files[name]
+		if !gosrc.MatchFile(name, content, true) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, content, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil || strings.HasSuffix(f.Name.Name, "_test") || f.Name.Name == "main" {
+			continue
+		}
+		if f.Doc != nil && symbols[0].doc == "" {
+			symbols[0].doc = f.Doc.Text()
+		}
+
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if !decl.Name.IsExported() {
+					continue
+				}
+				if decl.Recv == nil {
+					add(decl.Name.Name, kindFunc, decl.Doc)
+					continue
+				}
+				if len(decl.Recv.List) > 0 {
+					if recv := recvName(decl.Recv.List[0].Type); token.IsExported(recv) {
+						add(recv+"."+decl.Name.Name, kindMethod, decl.Doc)
+					}
+				}
+
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						if spec.Name.IsExported() {
+							add(spec.Name.Name, kindType, spec.Doc, decl.Doc)
+						}
+					case *ast.ValueSpec:
+						kind := kindVar
+						if decl.Tok == token.CONST {
+							kind = kindConst
+						}
+						for _, n := range spec.Names {
+							if n.IsExported() {
+								add(n.Name, kind, spec.Doc, decl.Doc)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return symbols
+}
+
+// docText returns the text of the first comment group that is set.
+func docText(docs ...*ast.CommentGroup) string {
+	for _, doc := range docs {
+		if doc != nil {
+			return doc.Text()
+		}
+	}
+	return ""
+}
+
+// symbolEntries returns the top-level symbols of the standard library, if it
+// is indexed already, and of the packages in the docs cache.
+func (b *botState) symbolEntries() []indexedSymbol {
+	entries := b.indexedSymbols(b.symbols.stdlib())
+
+	// Only top-level symbols can be searched for without their package.
+	n := 0
+	for _, s := range entries {
+		if s.kind != kindPackage && s.kind != kindMethod {
+			entries[n] = s
+			n++
+		}
+	}
+	return entries[:n]
+}
+
+// indexedSymbols returns the std symbols, and the symbols of the packages in
+// the docs cache.
+func (b *botState) indexedSymbols(std []indexedSymbol) []indexedSymbol {
+	seen := make(map[string]bool, len(std))
+	entries := make([]indexedSymbol, 0, len(std))
+	add := func(s indexedSymbol) {
+		if seen[s.query()] {
+			return
+		}
+		seen[s.query()] = true
+		entries = append(entries, s)
+	}
+
+	for _, s := range std {
+		add(s)
+	}
+	b.searcher.WithCache(func(cache map[string]*doc.CachedPackage) {
+		for _, cp := range cache {
+			for _, s := range cachedSymbols(cp.Package) {
+				add(s)
+			}
+		}
+	})
+	return entries
+}
+
+// cachedSymbols returns the package and its symbols, with their doc comments,
+// from the docs of a package. Packages of a specific version are skipped, as
+// the latest version is already cached too.
+func cachedSymbols(pkg doc.Package) []indexedSymbol {
+	importPath, version := splitVersion(pkg.URL)
+	if version != "" {
+		return nil
+	}
+
+	symbols := []indexedSymbol{{pkg: importPath, kind: kindPackage, doc: pkg.Overview.Text()}}
+	add := func(name string, kind symbolKind, comment doc.Comment) {
+		symbols = append(symbols, indexedSymbol{
+			pkg:  importPath,
+			name: name,
+			kind: kind,
+			doc:  comment.Text(),
+		})
+	}
+	for _, t := range pkg.Types {
+		add(t.Name, kindType, t.Comment)
+		for _, m := range t.Methods {
+			add(t.Name+"."+m.Name, kindMethod, m.Comment)
+		}
+	}
+	for _, fn := range pkg.Functions {
+		add(fn.Name, kindFunc, fn.Comment)
+	}
+	for _, c := range pkg.ConstantMap {
+		add(c.Name, kindConst, c.Comment)
+	}
+	for _, v := range pkg.VariableMap {
+		add(v.Name, kindVar, v.Comment)
+	}
+	return symbols
+}
+
+// unqualified reports whether the query is a single symbol name without a
+// package, such as "NewReader". Standard library packages and aliases are not
+// symbol names.
+func (b *botState) unqualified(query string) (string, bool) {
+	query = strings.TrimSpace(query)
+	if query == "" || strings.ContainsAny(query, "./@ ") {
+		return "", false
+	}
+
+	lower := strings.ToLower(query)
+	if stdlib[lower] || stdlibAliases[lower] != "" || b.cfg.Aliases[lower] != "" {
+		return "", false
+	}
+	return query, true
+}
+
+// resolveSymbol finds the packages that declare the symbol of an unqualified
+// query. If there is only one, the query of that symbol is returned, and if
+// there are more, the candidates are returned instead. Otherwise, the query
+// is returned unchanged. Right after a start, it waits for the standard
+// library to be indexed until ctx is done.
+func (b *botState) resolveSymbol(ctx context.Context, query string) (string, []indexedSymbol) {
+	name, ok := b.unqualified(query)
+	if !ok {
+		return query, nil
+	}
+
+	b.symbols.wait(ctx)
+	candidates := rankSymbols(name, b.symbolEntries())
+	switch len(candidates) {
+	case 0:
+		return query, nil
+	case 1:
+		return candidates[0].query(), nil
+	}
+	return query, candidates
+}
+
+// rankSymbols returns the symbols matching the name. Exact matches, ignoring
+// case, are returned if there are any, otherwise fuzzy matches are ranked by
+// their distance. Standard library packages, and shorter import paths, are
+// ranked first.
+func rankSymbols(name string, entries []indexedSymbol) []indexedSymbol {
+	less := func(a, b indexedSymbol) bool {
+		aStd, bStd := gosrc.IsStdlib(a.pkg), gosrc.IsStdlib(b.pkg)
+		if aStd != bStd {
+			return aStd
+		}
+		aDepth, bDepth := strings.Count(a.pkg, "/"), strings.Count(b.pkg, "/")
+		if aDepth != bDepth {
+			return aDepth < bDepth
+		}
+		return a.query() < b.query()
+	}
+
+	var matches []indexedSymbol
+	for _, s := range entries {
+		if strings.EqualFold(s.name, name) {
+			matches = append(matches, s)
+		}
+	}
+	if len(matches) > 0 {
+		sort.Slice(matches, func(i, j int) bool {
+			return less(matches[i], matches[j])
+		})
+		if len(matches) > maxSymbols {
+			matches = matches[:maxSymbols]
+		}
+		return matches
+	}
+
+	names := make([]string, len(entries))
+	for i, s := range entries {
+		names[i] = s.name
+	}
+	ranks := fuzzy.RankFindFold(name, names)
+	sort.SliceStable(ranks, func(i, j int) bool {
+		if ranks[i].Distance != ranks[j].Distance {
+			return ranks[i].Distance < ranks[j].Distance
+		}
+		return less(entries[ranks[i].OriginalIndex], entries[ranks[j].OriginalIndex])
+	})
+	if len(ranks) > maxSymbols {
+		ranks = ranks[:maxSymbols]
+	}
+	for _, r := range ranks {
+		matches = append(matches, entries[r.OriginalIndex])
+	}
+	return matches
+}
+
+// symbolPicker lists the candidates of an unqualified query, with a select
+// menu to choose one of them.
+func symbolPicker(id, name string, candidates []indexedSymbol) (discord.Embed, discord.ContainerComponents) {
+	var sb strings.Builder
+	opts := make([]discord.SelectOption, 0, len(candidates))
+	for _, c := range candidates {
+		fmt.Fprintf(&sb, "`%s` %s\n", c.query(), c.kindName())
+		opts = append(opts, discord.SelectOption{
+			Label:       c.query(),
+			Value:       c.query(),
+			Description: c.kindName(),
+		})
+	}
+
+	embed := discord.Embed{
+		Title:       "Symbol Search: " + name,
+		Description: sb.String(),
+		Footer: &discord.EmbedFooter{
+			Text: fmt.Sprintf("%d results, pick one to view its documentation", len(candidates)),
+		},
+		Color: accentColor,
+	}
+	return embed, pickerComponents(id, opts)
+}
+
+// pickerComponents returns a select menu of docs queries, and a hide button.
+// The select is handled by handlePickComponent.
+func pickerComponents(id string, opts []discord.SelectOption) discord.ContainerComponents {
+	return discord.ContainerComponents{
+		&discord.ActionRowComponent{
+			&discord.StringSelectComponent{
+				CustomID:    discord.ComponentID("docs.pick." + id),
+				Placeholder: "Choose a symbol",
+				Options:     opts,
+			},
+		},
+		&discord.ActionRowComponent{buttonComponent(id)},
+	}
+}
+
+// handlePickComponent replaces a symbol picker with the documentation of the
+// chosen symbol. Only the sender of the query can choose.
+func (b *botState) handlePickComponent(e *gateway.InteractionCreateEvent, component discord.ComponentInteraction, id string) {
+	sel, ok := component.(*discord.StringSelectInteraction)
+	if !ok || len(sel.Values) == 0 {
+		return
+	}
+
+	mu.Lock()
+	data, ok := interactionMap[id]
+	mu.Unlock()
+
+	var embed discord.Embed
+	switch {
+	case !ok:
+		embed = failEmbed("Error", expired)
+	case e.User.ID != data.userID:
+		embed = failEmbed("Error", notOwner)
+	}
+	if embed.Title != "" {
+		b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+			Type: api.MessageInteractionWithSource,
+			Data: &api.InteractionResponseData{
+				Flags:  discord.EphemeralMessage,
+				Embeds: &[]discord.Embed{embed},
+			},
+		})
+		return
+	}
+
+	query := sel.Values[0]
+	log.Printf("%s used docs pick(%q)", e.User.Tag(), query)
+
+	ctx, cancel := context.WithTimeout(context.Background(), componentTimeout)
+	defer cancel()
+
+	mu.Lock()
+	data.query = query
+	mu.Unlock()
+
+	embed, sym, more := b.interactionDocs(ctx, *e.User, data)
+	components := docsComponents(id, sym, false, more)
+	b.state.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+		Type: api.UpdateMessage,
+		Data: &api.InteractionResponseData{
+			Embeds:     &[]discord.Embed{embed},
+			Components: &components,
+		},
+	})
+}
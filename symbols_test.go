@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankSymbols(t *testing.T) {
+	entries := []indexedSymbol{
+		{pkg: "github.com/klauspost/compress/gzip", name: "NewReader", kind: kindFunc},
+		{pkg: "strings", name: "NewReader", kind: kindFunc},
+		{pkg: "compress/gzip", name: "NewReader", kind: kindFunc},
+		{pkg: "bufio", name: "NewReaderSize", kind: kindFunc},
+		{pkg: "bufio", name: "NewReader", kind: kindFunc},
+		{pkg: "encoding/json", name: "Marshal", kind: kindFunc},
+	}
+
+	var queries []string
+	for _, s := range rankSymbols("newreader", entries) {
+		queries = append(queries, s.query())
+	}
+	assert.Equal(t, []string{
+		"bufio.NewReader",
+		"strings.NewReader",
+		"compress/gzip.NewReader",
+		"github.com/klauspost/compress/gzip.NewReader",
+	}, queries)
+
+	fuzzy := rankSymbols("NewReaderSi", entries)
+	assert.Equal(t, []indexedSymbol{entries[3]}, fuzzy)
+
+	assert.Empty(t, rankSymbols("Unmarshal", entries))
+}
+
+func TestPackageSymbols(t *testing.T) {
+	pkg := &gosrc.Package{
+		ImportPath: "example.com/pkg",
+		Files: map[string][]byte{
+			"a.go": []byte(`// Package pkg reads things.
+package pkg
+
+type (
+	// Reader reads.
+	Reader struct{}
+	reader struct{}
+)
+
+// Read reads nothing.
+func (Reader) Read() {}
+
+func (reader) Read() {}
+
+// NewReader returns a Reader.
+func NewReader() Reader { return Reader{} }
+
+// Numbers.
+const A, B = 1, 2
+
+var ErrX error
+`),
+			"a_windows.go": []byte("package pkg\n\nfunc Windows() {}\n"),
+		},
+	}
+
+	assert.Equal(t, []indexedSymbol{
+		{pkg: "example.com/pkg", kind: kindPackage, doc: "Package pkg reads things.\n"},
+		{pkg: "example.com/pkg", name: "Reader", kind: kindType, doc: "Reader reads.\n"},
+		{pkg: "example.com/pkg", name: "Reader.Read", kind: kindMethod, doc: "Read reads nothing.\n"},
+		{pkg: "example.com/pkg", name: "NewReader", kind: kindFunc, doc: "NewReader returns a Reader.\n"},
+		{pkg: "example.com/pkg", name: "A", kind: kindConst, doc: "Numbers.\n"},
+		{pkg: "example.com/pkg", name: "B", kind: kindConst, doc: "Numbers.\n"},
+		{pkg: "example.com/pkg", name: "ErrX", kind: kindVar},
+	}, packageSymbols(pkg))
+}
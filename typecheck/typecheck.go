@@ -0,0 +1,313 @@
+// Package typecheck type-checks Go packages from source, as loaded by a
+// gosrc.Loader, to answer questions that the documentation alone can not,
+// such as which interfaces a type implements.
+package typecheck
+
+import (
+	"context"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DiscordGophers/dr-docso/gosrc"
+)
+
+// ErrNotChecked is returned by LookupChecked when the package of the name was
+// not type-checked yet.
+var ErrNotChecked = errors.New("package not type-checked")
+
+// Checker type-checks packages and caches the results. Imports from the
+// standard library and from the same module are type-checked as well. Other
+// imports are replaced with empty packages, as their versions are not known,
+// and every type that refers to them is invalid.
+//
+// Type errors are ignored, so that a package is still usable when some of its
+// imports could not be resolved.
+//
+// Each package is type-checked once at a time, while different packages are
+// type-checked concurrently. When more than max packages are cached, the
+// least recently used are evicted.
+type Checker struct {
+	loader gosrc.Loader
+	max    int
+
+	mu    sync.Mutex
+	pkgs  map[string]*checked
+	calls map[string]*checkCall
+}
+
+type checked struct {
+	pkg  *types.Package
+	used time.Time
+}
+
+// checkCall is a type-check in progress, which other checks of the same
+// package wait for.
+type checkCall struct {
+	done chan struct{}
+	pkg  *types.Package
+	err  error
+}
+
+// New creates a checker that loads package sources with loader, and caches up
+// to max type-checked packages. If max is 0, the cache is not limited.
+func New(loader gosrc.Loader, max int) *Checker {
+	return &Checker{
+		loader: loader,
+		max:    max,
+		pkgs:   map[string]*checked{},
+		calls:  map[string]*checkCall{},
+	}
+}
+
+// Check type-checks the package with the import path. An empty version refers
+// to the latest version.
+func (c *Checker) Check(ctx context.Context, importPath, version string) (*types.Package, error) {
+	return c.check(ctx, importPath, version, nil)
+}
+
+// Checked returns the package with the import path if it is already
+// type-checked, without loading it.
+func (c *Checker) Checked(importPath, version string) (*types.Package, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.pkgs[checkKey(importPath, version)]
+	if !ok {
+		return nil, false
+	}
+	entry.used = time.Now()
+	return entry.pkg, true
+}
+
+// Lookup returns the object that a qualified name, such as "io.Reader" or
+// "encoding/json.Marshaler", refers to. Names without a package, such as
+// "error", are looked up in the universe scope.
+func (c *Checker) Lookup(ctx context.Context, name string) (types.Object, error) {
+	return lookup(name, func(importPath string) (*types.Package, error) {
+		return c.Check(ctx, importPath, "")
+	})
+}
+
+// LookupChecked is like Lookup, but only looks up names in packages that are
+// already type-checked. Otherwise, it returns ErrNotChecked.
+func (c *Checker) LookupChecked(name string) (types.Object, error) {
+	return lookup(name, func(importPath string) (*types.Package, error) {
+		pkg, ok := c.Checked(importPath, "")
+		if !ok {
+			return nil, ErrNotChecked
+		}
+		return pkg, nil
+	})
+}
+
+func lookup(name string, check func(importPath string) (*types.Package, error)) (types.Object, error) {
+	dot := strings.LastIndex(name, ".")
+	if dot == -1 {
+		if obj := types.Universe.Lookup(name); obj != nil {
+			return obj, nil
+		}
+		return nil, gosrc.ErrNotFound
+	}
+
+	pkg, err := check(name[:dot])
+	if err != nil {
+		return nil, err
+	}
+	obj := pkg.Scope().Lookup(name[dot+1:])
+	if obj == nil {
+		return nil, gosrc.ErrNotFound
+	}
+	return obj, nil
+}
+
+func checkKey(importPath, version string) string {
+	if version != "" {
+		return importPath + "@" + version
+	}
+	return importPath
+}
+
+// check returns the cached package, or type-checks it. If the package is
+// already being type-checked, it waits for that check until ctx is done.
+// Parents are the packages that import it, which are being type-checked.
+func (c *Checker) check(ctx context.Context, importPath, version string, parents []string) (*types.Package, error) {
+	key := checkKey(importPath, version)
+
+	c.mu.Lock()
+	if entry, ok := c.pkgs[key]; ok {
+		entry.used = time.Now()
+		c.mu.Unlock()
+		return entry.pkg, nil
+	}
+	call, ok := c.calls[key]
+	if !ok {
+		call = &checkCall{done: make(chan struct{})}
+		c.calls[key] = call
+	}
+	c.mu.Unlock()
+
+	if ok {
+		select {
+		case <-call.done:
+			return call.pkg, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	call.pkg, call.err = c.typeCheck(ctx, importPath, version, append(slices.Clip(parents), key))
+
+	c.mu.Lock()
+	if call.err == nil {
+		c.add(key, call.pkg)
+	}
+	delete(c.calls, key)
+	c.mu.Unlock()
+	close(call.done)
+	return call.pkg, call.err
+}
+
+// add caches the package, and evicts the least recently used packages if
+// there are more than max. c.mu must be held.
+func (c *Checker) add(key string, pkg *types.Package) {
+	c.pkgs[key] = &checked{pkg: pkg, used: time.Now()}
+	for c.max > 0 && len(c.pkgs) > c.max {
+		var oldest string
+		for k, entry := range c.pkgs {
+			if k != key && (oldest == "" || entry.used.Before(c.pkgs[oldest].used)) {
+				oldest = k
+			}
+		}
+		delete(c.pkgs, oldest)
+	}
+}
+
+// typeCheck loads and type-checks the package. Each package has its own file
+// set, so that it is freed along with the package when it is evicted.
+func (c *Checker) typeCheck(ctx context.Context, importPath, version string, parents []string) (*types.Package, error) {
+	src, err := c.loader.Load(ctx, importPath, version)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range src.Names() {
+		content := src.Files[name]
+		if !gosrc.MatchFile(name, content, false) {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, path.Join(src.ImportPath, name), content, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		files = append(files, f)
+	}
+
+	conf := types.Config{
+		Importer:                 importer{ctx: ctx, c: c, module: src.Module, parents: parents},
+		Error:                    func(error) {},
+		IgnoreFuncBodies:         true,
+		DisableUnusedImportCheck: true,
+		FakeImportC:              true,
+	}
+	pkg, _ := conf.Check(importPath, fset, files, nil)
+	// Imports that timed out were replaced with empty packages, so the
+	// package is not cached.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// importer resolves the imports of a package that is being type-checked.
+type importer struct {
+	ctx     context.Context
+	c       *Checker
+	module  string
+	parents []string
+}
+
+func (i importer) Import(importPath string) (*types.Package, error) {
+	if importPath == "unsafe" {
+		return types.Unsafe, nil
+	}
+
+	// Import cycles are not valid, but would wait for themselves.
+	cycle := slices.Contains(i.parents, importPath)
+	sameModule := importPath == i.module || strings.HasPrefix(importPath, i.module+"/")
+	if (gosrc.IsStdlib(importPath) || sameModule) && !cycle {
+		if pkg, err := i.c.check(i.ctx, importPath, "", i.parents); err == nil {
+			return pkg, nil
+		}
+	}
+
+	pkg := types.NewPackage(importPath, path.Base(importPath))
+	pkg.MarkComplete()
+	return pkg, nil
+}
+
+// Implements reports whether the type implements the interface. If only the
+// pointer to the type implements it, ptr is true.
+func Implements(typ types.Type, iface *types.Interface) (ok, ptr bool) {
+	if types.Implements(typ, iface) {
+		return true, false
+	}
+	if _, isIface := typ.Underlying().(*types.Interface); isIface {
+		return false, false
+	}
+	return types.Implements(types.NewPointer(typ), iface), true
+}
+
+// Implementer is a type that implements an interface.
+type Implementer struct {
+	// Type is the implementing type.
+	Type *types.TypeName
+	// Pointer is true if only the pointer to the type implements the
+	// interface.
+	Pointer bool
+}
+
+// String formats the implementer as "pkg/path.T", or "*pkg/path.T".
+func (i Implementer) String() string {
+	s := i.Type.Pkg().Path() + "." + i.Type.Name()
+	if i.Pointer {
+		return "*" + s
+	}
+	return s
+}
+
+// Implementers returns the exported types of the package that implement the
+// interface, sorted by name. Interfaces and generic types are not included.
+func Implementers(pkg *types.Package, iface *types.Interface) []Implementer {
+	var impls []Implementer
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || !obj.Exported() || obj.IsAlias() {
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 || types.IsInterface(named) {
+			continue
+		}
+
+		if ok, ptr := Implements(named, iface); ok {
+			impls = append(impls, Implementer{Type: obj, Pointer: ptr})
+		}
+	}
+
+	sort.Slice(impls, func(i, j int) bool {
+		return impls[i].Type.Name() < impls[j].Type.Name()
+	})
+	return impls
+}
@@ -0,0 +1,123 @@
+package typecheck
+
+import (
+	"context"
+	"go/types"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImplementers(t *testing.T) {
+	root := gosrc.GOROOT(runtime.GOROOT())
+	if _, err := root.Version(); err != nil {
+		t.Skip("GOROOT has no VERSION file")
+	}
+
+	ctx := context.Background()
+	c := New(root, 0)
+
+	reader, err := c.Lookup(ctx, "io.Reader")
+	require.NoError(t, err)
+	iface := reader.Type().Underlying().(*types.Interface)
+
+	pkg, err := c.Check(ctx, "strings", "")
+	require.NoError(t, err)
+
+	var names []string
+	for _, impl := range Implementers(pkg, iface) {
+		names = append(names, impl.String())
+	}
+	assert.Equal(t, []string{"*strings.Reader"}, names)
+
+	errType, err := c.Lookup(ctx, "error")
+	require.NoError(t, err)
+	errIface := errType.Type().Underlying().(*types.Interface)
+
+	pathErr, err := c.Lookup(ctx, "io/fs.PathError")
+	require.NoError(t, err)
+	ok, ptr := Implements(pathErr.Type(), errIface)
+	assert.True(t, ok)
+	assert.True(t, ptr)
+
+	ok, _ = Implements(pathErr.Type(), iface)
+	assert.False(t, ok)
+}
+
+// mapLoader loads packages of the module example.com/m from a map of import
+// paths to the source of a file, and counts the loads.
+type mapLoader struct {
+	mu    sync.Mutex
+	src   map[string]string
+	loads map[string]int
+}
+
+func (l *mapLoader) Load(_ context.Context, importPath, _ string) (*gosrc.Package, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	src, ok := l.src[importPath]
+	if !ok {
+		return nil, gosrc.ErrNotFound
+	}
+	l.loads[importPath]++
+	return &gosrc.Package{
+		ImportPath: importPath,
+		Module:     "example.com/m",
+		Files:      map[string][]byte{"x.go": []byte(src)},
+	}, nil
+}
+
+func TestCheckerCache(t *testing.T) {
+	loader := &mapLoader{
+		src: map[string]string{
+			"example.com/m/a": "package a\nimport \"example.com/m/b\"\ntype A b.B",
+			"example.com/m/b": "package b\ntype B int",
+			"example.com/m/c": "package c\ntype C int",
+			// Import cycles are invalid, but must not wait for themselves.
+			"example.com/m/x": "package x\nimport \"example.com/m/y\"\ntype X y.Y",
+			"example.com/m/y": "package y\nimport \"example.com/m/x\"\ntype Y x.X",
+		},
+		loads: map[string]int{},
+	}
+	c := New(loader, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			obj, err := c.Lookup(ctx, "example.com/m/a.A")
+			if assert.NoError(t, err) {
+				assert.Equal(t, "int", obj.Type().Underlying().String())
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, loader.loads["example.com/m/a"])
+	assert.Equal(t, 1, loader.loads["example.com/m/b"])
+
+	_, ok := c.Checked("example.com/m/a", "")
+	assert.True(t, ok)
+	_, err := c.LookupChecked("example.com/m/c.C")
+	assert.ErrorIs(t, err, ErrNotChecked)
+
+	// Checking c evicts b, the least recently used package.
+	_, err = c.Check(ctx, "example.com/m/c", "")
+	require.NoError(t, err)
+	_, ok = c.Checked("example.com/m/b", "")
+	assert.False(t, ok)
+	_, ok = c.Checked("example.com/m/a", "")
+	assert.True(t, ok)
+
+	_, err = c.Check(ctx, "example.com/m/x", "")
+	assert.NoError(t, err)
+	assert.NoError(t, ctx.Err())
+}
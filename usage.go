@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// usage counts the docs queries of each module, so that the most used modules
+// can be prewarmed after a restart. The counts are saved to a file.
+type usage struct {
+	file string
+
+	mu     sync.Mutex
+	counts map[string]int
+	dirty  bool
+}
+
+// loadUsage loads the counts saved in file. If it does not exist, or cannot be
+// read, counting starts over.
+func loadUsage(file string) *usage {
+	u := &usage{file: file, counts: map[string]int{}}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return u
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &u.counts)
+	}
+	if err != nil {
+		log.Printf("Could not load usage, starting over: %v", err)
+		u.counts = map[string]int{}
+	}
+	return u
+}
+
+// record counts a query of the module.
+func (u *usage) record(module string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.counts[module]++
+	u.dirty = true
+}
+
+// count returns the number of queries of the module.
+func (u *usage) count(module string) int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.counts[module]
+}
+
+// top returns up to n of the most used modules, most used first.
+func (u *usage) top(n int) []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	modules := make([]string, 0, len(u.counts))
+	for module := range u.counts {
+		modules = append(modules, module)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		a, b := u.counts[modules[i]], u.counts[modules[j]]
+		if a != b {
+			return a > b
+		}
+		return modules[i] < modules[j]
+	})
+	return modules[:min(n, len(modules))]
+}
+
+// save saves the counts to the file, if they changed since they were last
+// saved.
+func (u *usage) save() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if !u.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(u.counts)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(u.file), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(u.file, data, 0o644); err != nil {
+		return err
+	}
+	u.dirty = false
+	return nil
+}
+
+// recordUsage counts a docs query of a module. It is only called when users
+// query docs, rather than for every message that renders them, such as when
+// paging or browsing.
+func (b *botState) recordUsage(query string) {
+	module, _ := parseQuery(query)
+	importPath, _ := splitVersion(b.modulePath(module))
+	b.usage.record(importPath)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"go/version"
+	"slices"
+	"strings"
+
+	"github.com/DiscordGophers/dr-docso/gosrc"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"golang.org/x/mod/semver"
+)
+
+// versionChoices returns the autocomplete choices for the version of a
+// module, in a query such as "github.com/hhhapz/doc@v1.". The versions of
+// modules are listed by the module proxy, and those of the standard library
+// are the Go releases. They are listed newest first.
+func (b *botState) versionChoices(query string) []discord.StringChoice {
+	typed, prefix, _ := strings.Cut(query, "@")
+	module, _ := parseQuery(typed)
+	importPath := b.modulePath(module)
+
+	var versions []string
+	if gosrc.IsStdlib(importPath) {
+		versions = goReleases(b.api.Releases(), b.goVersion())
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), autocompleteTimeout)
+		defer cancel()
+		_, versions, _ = b.proxy.Versions(ctx, importPath)
+	}
+	return versionOptions(typed, prefix, versions)
+}
+
+// goVersion returns the Go release of the GOROOT, or an empty string if it is
+// not known.
+func (b *botState) goVersion() string {
+	v, _ := gosrc.GOROOT(b.cfg.goroot()).Version()
+	return v
+}
+
+// versionOptions returns the choices for the versions of the module that
+// start with prefix, as well as latest. Pre-releases are flagged. Versions
+// whose query is longer than Discord allows for a value are left out, as they
+// cannot be truncated.
+func versionOptions(module, prefix string, versions []string) []discord.StringChoice {
+	var choices []discord.StringChoice
+	for _, v := range append([]string{"latest"}, versions...) {
+		value := module + "@" + v
+		if !strings.HasPrefix(v, prefix) || len(value) > 100 {
+			continue
+		}
+		name := value
+		if prerelease(v) {
+			name += " (pre-release)"
+		}
+		choices = append(choices, discord.StringChoice{Name: truncate(name, 100), Value: value})
+		if len(choices) == 25 {
+			break
+		}
+	}
+	if value := module + "@" + prefix; len(choices) == 0 && len(value) <= 100 {
+		choices = append(choices, discord.StringChoice{Name: value, Value: value})
+	}
+	return choices
+}
+
+// prerelease reports whether the version of a module, or the Go release, is
+// a pre-release, such as v1.2.0-rc.1 or go1.22rc1.
+func prerelease(v string) bool {
+	if strings.HasPrefix(v, "go") {
+		return strings.Contains(v, "rc") || strings.Contains(v, "beta")
+	}
+	return semver.Prerelease(v) != ""
+}
+
+// goReleases returns the tags of the Go releases, newest first: the releases
+// that have API files, and the release of the GOROOT, which is the only one
+// whose minor version is known. From Go 1.21, the first release of a version
+// is tagged with a ".0" suffix.
+func goReleases(releases []string, current string) []string {
+	tags := map[string]bool{}
+	for _, release := range releases {
+		if version.Compare(release, "go1.21") >= 0 && strings.Count(release, ".") == 1 {
+			release += ".0"
+		}
+		tags[release] = true
+	}
+	if version.IsValid(current) {
+		tags[current] = true
+	}
+
+	sorted := make([]string, 0, len(tags))
+	for tag := range tags {
+		sorted = append(sorted, tag)
+	}
+	slices.SortFunc(sorted, func(a, b string) int {
+		return version.Compare(b, a)
+	})
+	return sorted
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionOptions(t *testing.T) {
+	versions := []string{"v1.3.0-rc.1", "v1.2.1", "v1.2.0", "v0.9.0"}
+
+	assert.Equal(t, []discord.StringChoice{
+		{Name: "example.com/mod@latest", Value: "example.com/mod@latest"},
+		{Name: "example.com/mod@v1.3.0-rc.1 (pre-release)", Value: "example.com/mod@v1.3.0-rc.1"},
+		{Name: "example.com/mod@v1.2.1", Value: "example.com/mod@v1.2.1"},
+		{Name: "example.com/mod@v1.2.0", Value: "example.com/mod@v1.2.0"},
+		{Name: "example.com/mod@v0.9.0", Value: "example.com/mod@v0.9.0"},
+	}, versionOptions("example.com/mod", "", versions))
+
+	assert.Equal(t, []discord.StringChoice{
+		{Name: "example.com/mod@v1.2.1", Value: "example.com/mod@v1.2.1"},
+		{Name: "example.com/mod@v1.2.0", Value: "example.com/mod@v1.2.0"},
+	}, versionOptions("example.com/mod", "v1.2", versions))
+
+	assert.Equal(t, []discord.StringChoice{
+		{Name: "example.com/mod@v2", Value: "example.com/mod@v2"},
+	}, versionOptions("example.com/mod", "v2", versions))
+
+	assert.Equal(t, []discord.StringChoice{
+		{Name: "fmt@go1.22rc1 (pre-release)", Value: "fmt@go1.22rc1"},
+	}, versionOptions("fmt", "go1.22", []string{"go1.22rc1"}))
+
+	// Queries over the limit of a value are left out, rather than truncated.
+	long := "example.com/" + strings.Repeat("a", 78)
+	assert.Equal(t, []discord.StringChoice{
+		{Name: long + "@latest", Value: long + "@latest"},
+		{Name: long + "@v1.0.0", Value: long + "@v1.0.0"},
+	}, versionOptions(long, "", []string{"v1.0.0", "v1.0.0-rc.1"}))
+	assert.Empty(t, versionOptions(long, "v1.0.0-rc.1", nil))
+}
+
+func TestGoReleases(t *testing.T) {
+	assert.Equal(t,
+		[]string{"go1.22.3", "go1.22.0", "go1.21.0", "go1.20", "go1"},
+		goReleases([]string{"go1.20", "go1.22", "go1", "go1.21"}, "go1.22.3"))
+	assert.Equal(t,
+		[]string{"go1.21.0", "go1.20"},
+		goReleases([]string{"go1.20", "go1.21"}, "devel go1.23-abcdef"))
+}